@@ -1,22 +1,31 @@
 package chain
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/config"
 	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/plugins"
 	"github.com/civet148/cosmos-cli/shells"
 	"github.com/civet148/cosmos-cli/types"
 	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/cosmos-cli/workspace"
 	"github.com/civet148/log"
 	"github.com/imdario/mergo"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 type ChainBuilder struct {
 	peers             map[string]*types.NodePeer //node peer information
+	versions          map[string]string          //node binary version of validators
+	dialects          map[string]*shells.Dialect //command dialect of node binaries
 	option            *types.Option              //init option
 	nNodeCount        int                        //node count
 	strNode0Home      string                     //node0 home
@@ -24,23 +33,50 @@ type ChainBuilder struct {
 	strKeyFile        string                     //key file to save
 	igniteConfigs     map[string]interface{}     //ignite config map
 	maker             *shells.ChainMaker         //shell maker
+	network           *workspace.Network         //named network in workspace
+	report            *types.BuildReport         //build report
+}
+
+type buildStage struct {
+	Name string
+	Run  func(ic *types.IgniteConfig) error
 }
 
 func NewChainBuilder(opt *types.Option) api.ManagerApi {
 	if opt == nil {
 		panic("init option is nil")
 	}
-	maker := shells.NewChainMaker(opt.NodeCmd, opt.ChainID, opt.DefaultDenom, opt.KeyPhrase, opt.KeyringBackend)
+	var network *workspace.Network
+	if opt.Network != "" {
+		network = workspace.New("").Network(opt.Network)
+		opt.ConfigPath = network.ConfigPath()
+		opt.HomeRoot = network.HomesDir()
+		if opt.ReportPath == "" {
+			opt.ReportPath = network.ReportPath()
+		}
+	}
+	strNodeCmd := opt.NodeCmd
+	if strNodeCmd == "" {
+		strNodeCmd = types.DEFAULT_NODE_CMD
+	}
+	maker := shells.NewChainMaker(strNodeCmd, opt.ChainID, opt.DefaultDenom, opt.KeyPhrase, opt.KeyringBackend)
 	return &ChainBuilder{
 		option:        opt,
 		maker:         maker,
+		network:       network,
 		peers:         make(map[string]*types.NodePeer),
+		versions:      make(map[string]string),
+		dialects:      make(map[string]*shells.Dialect),
 		strKeyFile:    types.EXPORT_KEY_FILE,
 		igniteConfigs: make(map[string]interface{}),
+		report:        &types.BuildReport{},
 	}
 }
 
 func (m *ChainBuilder) Run() (err error) {
+	if err = m.openNetwork(); err != nil {
+		return err
+	}
 	var ic *types.IgniteConfig
 	if ic, err = m.parseConfig(); err != nil {
 		return log.Errorf(err.Error())
@@ -52,47 +88,106 @@ func (m *ChainBuilder) Run() (err error) {
 	if err != nil {
 		return err
 	}
-	err = m.initNodes(ic)
-	if err != nil {
+	var stages = []*buildStage{
+		{Name: types.STAGE_BUILD_BINARY, Run: m.buildBinary},
+		{Name: types.STAGE_PREFLIGHT, Run: m.preflight},
+		{Name: types.STAGE_INIT_NODES, Run: m.initNodes},
+		{Name: types.STAGE_UPDATE_APP_CONFIG, Run: m.updateAppConfig},
+		{Name: types.STAGE_UPDATE_COSMOS_CONFIG, Run: m.updateCosmosConfig},
+		{Name: types.STAGE_UPDATE_CLIENT_CONFIG, Run: m.updateClientConfig},
+		{Name: types.STAGE_MERGE_GENESIS, Run: m.mergeGenesisConfig},
+		{Name: types.STAGE_SYNC_GENESIS, Run: m.syncGenesisFile},
+		{Name: types.STAGE_COSMOVISOR_LAYOUT, Run: m.layoutCosmovisor},
+		{Name: types.STAGE_VERIFY, Run: m.verifyNetwork},
+		{Name: types.STAGE_SHOW_VALIDATORS, Run: m.showValidators},
+	}
+	if err = m.checkHooks(ic, stages); err != nil {
 		return err
 	}
-	err = m.updateAppConfig(ic)
-	if err != nil {
-		return err
+	for _, stage := range stages {
+		if err = m.runHooks(ic, stage.Name, types.HOOK_BEFORE); err == nil {
+			if err = stage.Run(ic); err == nil {
+				err = m.runHooks(ic, stage.Name, types.HOOK_AFTER)
+			}
+		}
+		m.journal(types.JOURNAL_CMD_BUILD, stage.Name, err)
+		if err != nil {
+			return err
+		}
 	}
-	err = m.updateCosmosConfig(ic)
-	if err != nil {
-		return err
+	return m.writeReport(ic)
+}
+
+// openNetwork checks named network exists and redirects logs into it
+func (m *ChainBuilder) openNetwork() error {
+	if m.network == nil {
+		return nil
 	}
-	err = m.mergeGenesisConfig(ic)
-	if err != nil {
-		return err
+	if !m.network.Exists() {
+		return log.Errorf("network %s not found, please create it by 'network create' command", m.network.Name)
 	}
-	err = m.syncGenesisFile(ic)
-	if err != nil {
-		return err
+	if err := log.Open(m.network.LogPath()); err != nil {
+		log.Warnf("open network log file %s error [%s]", m.network.LogPath(), err)
+	}
+	return nil
+}
+
+// journal records stage result into state journal of named network
+func (m *ChainBuilder) journal(strCommand, strStage string, err error) {
+	if m.network == nil {
+		return
+	}
+	entry := &workspace.JournalEntry{
+		Command: strCommand,
+		Stage:   strStage,
+		Status:  types.JOURNAL_STATUS_OK,
 	}
-	err = m.showValidators(ic)
 	if err != nil {
-		return err
+		entry.Status = types.JOURNAL_STATUS_FAILED
+		entry.Error = err.Error()
 	}
+	if err = m.network.Journal(entry); err != nil {
+		log.Warnf("write network %s journal error [%s]", m.network.Name, err)
+	}
+}
+
+// writeReport saves build report if report path specified
+func (m *ChainBuilder) writeReport(ic *types.IgniteConfig) (err error) {
+	strPath := m.option.ReportPath
+	if strPath == "" {
+		return nil
+	}
+	m.report.ChainID = ic.Genesis.ChainID
+	m.report.NodeCmd = m.option.NodeCmd
+	m.report.ConfigPath = m.option.ConfigPath
+	m.report.Time = time.Now()
+	cf := confile.New(confile.DefaultJSONEncodingCreator, strPath)
+	if err = cf.SaveJSON(m.report); err != nil {
+		return log.Errorf("save build report %s error [%s]", strPath, err)
+	}
+	if m.network != nil {
+		strHistory := filepath.Join(m.network.ReportsDir(), fmt.Sprintf("build-report-%s.json", m.report.Time.Format(types.TIME_FORMAT_FILE_NAME)))
+		if err = confile.New(confile.DefaultJSONEncodingCreator, strHistory).SaveJSON(m.report); err != nil {
+			return log.Errorf("save build report %s error [%s]", strHistory, err)
+		}
+	}
+	log.Infof("build report saved to %s", strPath)
 	return nil
 }
 
+// parseConfig renders config file and overlays (environment variables and Go template), merges and decodes them
 func (m *ChainBuilder) parseConfig() (ic *types.IgniteConfig, err error) {
-	vip := viper.New()
-	strPath := m.option.ConfigPath
-	vip.SetConfigFile(strPath)
-	vip.SetConfigType("yaml")
-	if err = vip.ReadInConfig(); err != nil {
-		return nil, log.Errorf("load config [%s] error [%s]", strPath, err.Error())
-	}
-	m.igniteConfigs = vip.AllSettings()
 	strConfig := m.option.ConfigPath
-	data, err := os.ReadFile(strConfig)
+	data, err := config.Load(append([]string{strConfig}, m.option.ConfigOverlays...)...)
 	if err != nil {
 		return nil, log.Errorf("open config file %s error [%v]", strConfig, err)
 	}
+	vip := viper.New()
+	vip.SetConfigType("yaml")
+	if err = vip.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, log.Errorf("load config [%s] error [%s]", strConfig, err.Error())
+	}
+	m.igniteConfigs = vip.AllSettings()
 	ic = &types.IgniteConfig{}
 	err = yaml.Unmarshal(data, ic)
 	if err != nil {
@@ -101,9 +196,73 @@ func (m *ChainBuilder) parseConfig() (ic *types.IgniteConfig, err error) {
 	if ic.Genesis.ChainID != m.option.ChainID {
 		m.option.ChainID = ic.Genesis.ChainID
 	}
+	//node command flag > build.binary of config file > default node command
+	if m.option.NodeCmd == "" {
+		m.option.NodeCmd = ic.Build.Binary
+	}
+	if m.option.NodeCmd == "" {
+		m.option.NodeCmd = types.DEFAULT_NODE_CMD
+	}
+	m.maker = shells.NewChainMaker(m.option.NodeCmd, m.option.ChainID, m.option.DefaultDenom, m.option.KeyPhrase, m.option.KeyringBackend)
 	return ic, nil
 }
 
+// makerOf returns chain maker running node binary of validator in the command dialect probed for the binary
+func (m *ChainBuilder) makerOf(v *types.ValidatorConfig) *shells.ChainMaker {
+	maker := m.maker.WithNodeCmd(v.Binary)
+	return maker.WithDialect(m.dialects[maker.NodeCmd()])
+}
+
+// probeDialects probes command dialect of every distinct node binary of validators
+func (m *ChainBuilder) probeDialects(ic *types.IgniteConfig) {
+	for _, v := range ic.Validators {
+		m.dialectOf(m.maker.WithNodeCmd(v.Binary))
+	}
+	m.maker = m.maker.WithDialect(m.dialectOf(m.maker))
+}
+
+// dialectOf probes command dialect of node binary of maker by its long version and help output, a binary is
+// probed only once
+func (m *ChainBuilder) dialectOf(maker *shells.ChainMaker) *shells.Dialect {
+	strBin := maker.NodeCmd()
+	if d, ok := m.dialects[strBin]; ok {
+		return d
+	}
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	strVersion, _ := cmd.Shell(maker.MakeCmdLineVersionLong())
+	strHelp, _ := cmd.Shell(maker.MakeCmdLineHelp())
+	m.dialects[strBin] = shells.ProbeDialect(strVersion, strHelp)
+	log.Infof("node binary %s command dialect %s", strBin, m.dialects[strBin].Name)
+	return m.dialects[strBin]
+}
+
+// preflight checks node binary of every validator exists and reports its version
+func (m *ChainBuilder) preflight(ic *types.IgniteConfig) (err error) {
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	var versions = make(map[string]string)
+	for _, v := range ic.Validators {
+		maker := m.makerOf(&v)
+		strBin := maker.NodeCmd()
+		if _, ok := versions[strBin]; !ok {
+			var strPath, output string
+			if strPath, err = exec.LookPath(strBin); err != nil {
+				return log.Errorf("validator [%s] node binary %s not found [%s]", v.Name, strBin, err)
+			}
+			if output, err = cmd.Shell(maker.MakeCmdLineVersion()); err != nil {
+				return log.Errorf("validator [%s] node binary %s version error [%s]", v.Name, strBin, err)
+			}
+			versions[strBin] = utils.LastLine(output)
+			fmt.Printf("node binary %s (%s) version %s\n", strBin, strPath, versions[strBin])
+		}
+		m.versions[v.Name] = versions[strBin]
+	}
+	if len(versions) > 1 {
+		log.Warnf("mixed-version network with %d node binaries", len(versions))
+	}
+	m.probeDialects(ic)
+	return nil
+}
+
 func (m *ChainBuilder) checkConfig(ic *types.IgniteConfig) error {
 
 	if len(ic.Validators) == 0 {
@@ -153,20 +312,176 @@ func (m *ChainBuilder) checkConfig(ic *types.IgniteConfig) error {
 			return log.Errorf("account [%v] coins is empty", v.Name)
 		}
 	}
-	strHome := ic.Validators[0].Home
-	if strings.HasPrefix(strHome, "~") {
-		strHome = strings.Replace(strHome, "~", "$HOME", -1)
-		strHome = os.ExpandEnv(strHome)
-		ic.Validators[0].Home = strHome
+	if err := m.checkValidatorSettings(ic); err != nil {
+		return err
 	}
+	m.resolveHomes(ic)
 	m.strNode0Home = ic.Validators[0].Home
 	m.strNode0Validator = ic.Validators[0].Name
 	return nil
 }
 
+// validatorSettingSchemas are TOML schemas of validator setting sections by section name
+var validatorSettingSchemas = map[string]interface{}{
+	"app":    types.AppConfig{},
+	"config": types.CosmosConfig{},
+	"client": types.ClientConfig{},
+}
+
+// validatorSettingDefaults are default values of validator setting sections by section name, client defaults
+// go into global client section so client section of a validator never overrides global one by defaults
+var validatorSettingDefaults = map[string]interface{}{
+	"app":    types.DefaultAppConfig(),
+	"config": types.DefaultCosmosConfig(),
+}
+
+// clientDefaults is schema of global client section, openapi is the client code generation setting of ignite
+type clientDefaults struct {
+	types.ClientConfig
+	Openapi interface{} `toml:"openapi"`
+}
+
+// checkValidatorSettings type checks app/config/client sections of every validator (and global client section)
+// against TOML schemas of app.toml, config.toml and client.toml, coerces values to the types of schema and warns
+// keys not in schema (eg. a typo). defaults of the typed models are applied for keys absent in config file, keys
+// without a default keep the values written by init of the node binary.
+func (m *ChainBuilder) checkValidatorSettings(ic *types.IgniteConfig) error {
+	conf, ok := m.igniteConfigs["client"].(map[string]interface{})
+	if !ok {
+		conf = make(map[string]interface{})
+		m.igniteConfigs["client"] = conf
+	}
+	unknown, err := confile.CheckSchema(clientDefaults{}, conf)
+	if err != nil {
+		return log.Errorf("client %s", err)
+	}
+	if len(unknown) != 0 {
+		log.Warnf("client keys not found in schema: %s", strings.Join(unknown, ", "))
+	}
+	confile.ApplyDefaults(types.DefaultClientConfig(), conf)
+	vals, _ := m.igniteConfigs["validators"].([]interface{})
+	for i, val := range vals {
+		igniteSettings, ok := val.(map[string]interface{})
+		if !ok || i >= len(ic.Validators) {
+			continue
+		}
+		strName := ic.Validators[i].Name
+		for _, strSection := range []string{"app", "config", "client"} {
+			conf, ok := igniteSettings[strSection].(map[string]interface{})
+			if !ok {
+				conf = make(map[string]interface{})
+				igniteSettings[strSection] = conf
+			}
+			unknown, err := confile.CheckSchema(validatorSettingSchemas[strSection], conf)
+			if err != nil {
+				return log.Errorf("validator [%s] %s %s", strName, strSection, err)
+			}
+			if len(unknown) != 0 {
+				log.Warnf("validator [%s] %s keys not found in schema: %s", strName, strSection, strings.Join(unknown, ", "))
+			}
+			if defaults, ok := validatorSettingDefaults[strSection]; ok {
+				confile.ApplyDefaults(defaults, conf)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveHomes expands ~ of validator homes and places homes under home root if specified
+func (m *ChainBuilder) resolveHomes(ic *types.IgniteConfig) {
+	strRoot := m.option.HomeRoot
+	for i := range ic.Validators {
+		v := &ic.Validators[i]
+		v.Home = workspace.ExpandHome(v.Home)
+		if strRoot == "" {
+			continue
+		}
+		if !filepath.IsAbs(v.Home) {
+			v.Home = filepath.Join(strRoot, v.Home)
+		} else if !strings.HasPrefix(filepath.Clean(v.Home), filepath.Clean(strRoot)+string(filepath.Separator)) {
+			strHome := filepath.Join(strRoot, v.Name)
+			log.Infof("validator [%s] home %s remapped to %s", v.Name, v.Home, strHome)
+			v.Home = strHome
+		}
+	}
+}
+
+// allowedRoots returns root directories validator homes are allowed to be deleted inside. the homes directory
+// of named network is always allowed, the home root or workspace root is taken if no root specified.
+func (m *ChainBuilder) allowedRoots() []string {
+	opt := m.option
+	allowedRoots := opt.AllowedRoots
+	if m.network != nil {
+		allowedRoots = append(allowedRoots, m.network.HomesDir())
+	}
+	if len(allowedRoots) != 0 {
+		return allowedRoots
+	}
+	if opt.HomeRoot != "" {
+		return []string{opt.HomeRoot}
+	}
+	return []string{workspace.New("").Root()}
+}
+
+// prepareHomes checks every validator home is safe to delete, asks for confirmation if any home contains
+// chain data, archives existing homes if backup enabled and then wipes them. a non-empty home without
+// chain data is only deleted with --force.
+func (m *ChainBuilder) prepareHomes(ic *types.IgniteConfig) (err error) {
+	opt := m.option
+	allowedRoots := m.allowedRoots()
+	var existHomes []string
+	for _, v := range ic.Validators {
+		if err = utils.CheckRemovableHome(v.Home, allowedRoots); err != nil {
+			return log.Errorf("validator [%s] %s", v.Name, err)
+		}
+		if utils.HasChainData(v.Home) {
+			existHomes = append(existHomes, v.Home)
+		} else if !utils.IsEmptyDir(v.Home) && !opt.Force {
+			return log.Errorf("validator [%s] home %s is not empty and contains no chain data, use --force to delete it", v.Name, v.Home)
+		}
+	}
+	if len(existHomes) != 0 && !opt.Force {
+		if !utils.Confirm(fmt.Sprintf("validator homes %v contain existing chain data, delete them?", existHomes)) {
+			return log.Errorf("validator homes %v contain existing chain data, use --force to delete them", existHomes)
+		}
+	}
+	if opt.Backup {
+		for _, strHome := range existHomes {
+			if err = m.backupHome(strHome); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range ic.Validators {
+		if err = os.RemoveAll(v.Home); err != nil {
+			return log.Errorf(err.Error())
+		}
+	}
+	return nil
+}
+
+// backupHome archives validator home into a timestamped tarball
+func (m *ChainBuilder) backupHome(strHome string) (err error) {
+	strDir := m.option.BackupDir
+	if strDir == "" {
+		if m.network != nil {
+			strDir = m.network.BackupsDir()
+		} else {
+			strDir = filepath.Dir(filepath.Clean(strHome))
+		}
+	}
+	strName := filepath.Base(filepath.Clean(strHome))
+	strFile := filepath.Join(strDir, fmt.Sprintf("%s-%s.tar.gz", strName, time.Now().Format(types.TIME_FORMAT_FILE_NAME)))
+	if err = utils.TarGz(strHome, strFile, strName); err != nil {
+		return log.Errorf("backup home %s to %s error [%s]", strHome, strFile, err)
+	}
+	log.Infof("home %s archived to %s", strHome, strFile)
+	return nil
+}
+
 func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 	opt := m.option
-	maker := m.maker
+	node0 := m.makerOf(&ic.Validators[0])
 
 	cmd := utils.NewCmdExecutor(opt.Debug)
 	var reenter = true
@@ -175,25 +490,14 @@ func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 	if m.option.KeyringBackend == types.KEYRING_BACKEND_TEST {
 		passwd = false
 	}
-	for _, v := range ic.Validators {
-		err = os.RemoveAll(v.Home)
-		if err != nil {
-			log.Errorf(err.Error())
-			return
-		}
-
+	if err = m.prepareHomes(ic); err != nil {
+		return err
+	}
+	for i, v := range ic.Validators {
+		maker := m.makerOf(&v)
 		//chain config and data init
-		cmdline = maker.MakeCmdLineConfigKeyringBackend(v.Home)
-		_, err = cmd.Shell(cmdline)
-		if err != nil {
-			log.Errorf(err.Error())
-			return
-		}
-		cmdline = maker.MakeCmdLineConfigChainID(v.Home)
-		_, err = cmd.Shell(cmdline)
-		if err != nil {
-			log.Errorf(err.Error())
-			return
+		if err = m.updateValidatorClientConfig(ic, i, &v); err != nil {
+			return err
 		}
 		cmdline = maker.MakeCmdLineInit(v.Config.Moniker, v.Home)
 		_, err = cmd.Shell(cmdline)
@@ -202,7 +506,7 @@ func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 			return
 		}
 		//add all validator account key to first validator keyring
-		cmdline = maker.MakeCmdLineKeysAdd(v.Name, m.strNode0Home, reenter, passwd)
+		cmdline = node0.MakeCmdLineKeysAdd(v.Name, m.strNode0Home, reenter, passwd)
 		_, err = cmd.Shell(cmdline)
 		if err != nil {
 			log.Errorf(err.Error())
@@ -229,7 +533,7 @@ func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 
 		//add all validator genesis account to first validator
 		balances := ic.GetAccountBalances(v.Name)
-		cmdline = maker.MakeCmdLineAddGenesisAccount(v.Name, m.strNode0Home, balances, passwd)
+		cmdline = node0.MakeCmdLineAddGenesisAccount(v.Name, m.strNode0Home, balances, passwd)
 		_, err = cmd.Shell(cmdline)
 		if err != nil {
 			log.Errorf(err.Error())
@@ -270,28 +574,40 @@ func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 			}
 		}
 		//get node id and make peer info
-		var strNodeId string
-		cmdline = maker.MakeCmdLineShowNodeId(v.Home)
-		strNodeId, err = cmd.Shell(cmdline)
+		var np *types.NodePeer
+		np, err = m.makeNodePeer(cmd, ic, v.Name, v.Home)
+		if err != nil {
+			return
+		}
+		m.peers[v.Name] = np
+	}
+	//add non-validator account (eg. faucet) key and genesis account to first validator
+	for _, a := range ic.Accounts {
+		if _, v := ic.GetValidator(a.Name); v != nil {
+			continue
+		}
+		cmdline = node0.MakeCmdLineKeysAdd(a.Name, m.strNode0Home, false, passwd)
+		_, err = cmd.Shell(cmdline)
 		if err != nil {
 			log.Errorf(err.Error())
 			return
 		}
-		np := &types.NodePeer{
-			Name: v.Name,
-			Peer: fmt.Sprintf("%s@%s", strNodeId, ic.GetValidatorHost(v.Name)),
+		cmdline = node0.MakeCmdLineAddGenesisAccount(a.Name, m.strNode0Home, ic.GetAccountBalances(a.Name), passwd)
+		_, err = cmd.Shell(cmdline)
+		if err != nil {
+			log.Errorf(err.Error())
+			return
 		}
-		m.peers[v.Name] = np
 	}
 
 	//collect gentxs for first validator
-	cmdline = maker.MakeCmdLineCollectGenTxs(m.strNode0Home)
+	cmdline = node0.MakeCmdLineCollectGenTxs(m.strNode0Home)
 	_, err = cmd.Shell(cmdline)
 	if err != nil {
 		log.Errorf(err.Error())
 		return
 	}
-	cmdline = maker.MakeCmdLineValidateGenesis(m.strNode0Home)
+	cmdline = node0.MakeCmdLineValidateGenesis(m.strNode0Home)
 	_, err = cmd.Shell(cmdline)
 	if err != nil {
 		log.Errorf(err.Error())
@@ -303,99 +619,175 @@ func (m *ChainBuilder) initNodes(ic *types.IgniteConfig) (err error) {
 		if np == nil {
 			return log.Errorf("validator %s peer info not found", v.Name)
 		}
-		for _, p := range m.peers {
-			if p.Name != v.Name {
-				np.PersistentPeers = append(np.PersistentPeers, p.Peer)
-			}
-		}
+		np.PersistentPeers = m.makePersistentPeers(v.Name)
 	}
 	return nil
 }
 
-func (m *ChainBuilder) updateAppConfig(ic *types.IgniteConfig) (err error) {
-	vals := m.igniteConfigs["validators"].([]interface{})
-	for i, v := range ic.Validators {
-		vip := viper.New()
-		strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_APP)
-		vip.SetConfigFile(strPath)
-		vip.SetConfigType("toml")
-		if err = vip.ReadInConfig(); err != nil {
-			return log.Errorf("load config [%s] error [%s]", strPath, err.Error())
-		}
-		var genesis = make(map[string]interface{})
-		genesis = vip.AllSettings()
-		cf := confile.New(confile.DefaultTOMLEncodingCreator, strPath)
-		if err = cf.Load(&genesis); err != nil {
-			return err
+// makeNodePeer queries node id from validator home and makes its peer information
+func (m *ChainBuilder) makeNodePeer(cmd *utils.CmdExecutor, ic *types.IgniteConfig, strName, strHome string) (np *types.NodePeer, err error) {
+	var strNodeId string
+	maker := m.maker
+	if _, v := ic.GetValidator(strName); v != nil {
+		maker = m.makerOf(v)
+	}
+	cmdline := maker.MakeCmdLineShowNodeId(strHome)
+	strNodeId, err = cmd.Shell(cmdline)
+	if err != nil {
+		return nil, log.Errorf(err.Error())
+	}
+	return &types.NodePeer{
+		Name: strName,
+		Peer: fmt.Sprintf("%s@%s", strNodeId, ic.GetValidatorHost(strName)),
+	}, nil
+}
+
+// makePersistentPeers makes persistent peers of validator from all the other known peers
+func (m *ChainBuilder) makePersistentPeers(strName string) (peers []string) {
+	for _, p := range m.peers {
+		if p.Name != strName {
+			peers = append(peers, p.Peer)
 		}
-		igniteSettings := vals[i].(map[string]interface{})
-		conf := igniteSettings["app"]
-		log.Json("app config to update", conf)
-		if err = mergo.Merge(&genesis, conf, mergo.WithOverride); err != nil {
+	}
+	return peers
+}
+
+func (m *ChainBuilder) updateAppConfig(ic *types.IgniteConfig) (err error) {
+	for i := range ic.Validators {
+		if err = m.updateValidatorAppConfig(i, &ic.Validators[i]); err != nil {
 			return err
 		}
-		err = cf.Save(genesis)
 	}
-	return
+	return nil
 }
 
-func (m *ChainBuilder) updateCosmosConfig(ic *types.IgniteConfig) (err error) {
+func (m *ChainBuilder) updateValidatorAppConfig(i int, v *types.ValidatorConfig) (err error) {
 	vals := m.igniteConfigs["validators"].([]interface{})
-	for i, v := range ic.Validators {
-		vip := viper.New()
-		strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_CONFIG)
-		vip.SetConfigFile(strPath)
-		vip.SetConfigType("toml")
-		if err = vip.ReadInConfig(); err != nil {
-			return log.Errorf("load config [%s] error [%s]", strPath, err.Error())
-		}
-		//update validator p2p persistent peers
-		var strPeers string
-		if np, ok := m.peers[v.Name]; ok {
-			strPeers = strings.Join(np.PersistentPeers, ",")
-			vip.Set("p2p.persistent_peers", strPeers)
-			log.Infof("[%s] p2p.persistent_peers=%s", v.Name, strPeers)
-		}
-		var genesis = make(map[string]interface{})
-		genesis = vip.AllSettings()
-		cf := confile.New(confile.DefaultTOMLEncodingCreator, strPath)
-		if err = cf.Load(&genesis); err != nil {
+	strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_APP)
+	igniteSettings := vals[i].(map[string]interface{})
+	conf, _ := igniteSettings["app"].(map[string]interface{})
+	log.Json("app config to update", conf)
+	if err = m.tomlFile(strPath).EditTOML(conf); err != nil {
+		return log.Errorf("update config [%s] error [%s]", strPath, err.Error())
+	}
+	return nil
+}
+
+// tomlFile returns TOML config file of validator home, the previous content is kept as <path>.bak when backup
+// is enabled
+func (m *ChainBuilder) tomlFile(strPath string) *confile.ConfigFile {
+	c := confile.New(confile.DefaultTOMLEncodingCreator, strPath)
+	if m.option.Backup {
+		c.WithBackup()
+	}
+	return c
+}
+
+func (m *ChainBuilder) updateCosmosConfig(ic *types.IgniteConfig) (err error) {
+	for i := range ic.Validators {
+		if err = m.updateValidatorCosmosConfig(i, &ic.Validators[i]); err != nil {
 			return err
 		}
-		igniteSettings := vals[i].(map[string]interface{})
-		conf := igniteSettings["config"].(map[string]interface{})
-		p2p := conf["p2p"].(map[string]interface{})
+	}
+	return nil
+}
+
+func (m *ChainBuilder) updateValidatorCosmosConfig(i int, v *types.ValidatorConfig) (err error) {
+	vals := m.igniteConfigs["validators"].([]interface{})
+	strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_CONFIG)
+	igniteSettings := vals[i].(map[string]interface{})
+	conf, ok := igniteSettings["config"].(map[string]interface{})
+	if !ok {
+		conf = make(map[string]interface{})
+		igniteSettings["config"] = conf
+	}
+	//update validator p2p persistent peers
+	if np, ok := m.peers[v.Name]; ok {
+		p2p, ok := conf["p2p"].(map[string]interface{})
+		if !ok {
+			p2p = make(map[string]interface{})
+			conf["p2p"] = p2p
+		}
+		strPeers := strings.Join(np.PersistentPeers, ",")
 		p2p["persistent_peers"] = strPeers
-		log.Json("cosmos config to update", conf)
-		if err = mergo.Merge(&genesis, conf, mergo.WithOverride); err != nil {
+		log.Infof("[%s] p2p.persistent_peers=%s", v.Name, strPeers)
+	}
+	log.Json("cosmos config to update", conf)
+	if err = m.tomlFile(strPath).EditTOML(conf); err != nil {
+		return log.Errorf("update config [%s] error [%s]", strPath, err.Error())
+	}
+	return nil
+}
+
+func (m *ChainBuilder) updateClientConfig(ic *types.IgniteConfig) (err error) {
+	for i := range ic.Validators {
+		if err = m.updateValidatorClientConfig(ic, i, &ic.Validators[i]); err != nil {
 			return err
 		}
-		err = cf.Save(genesis)
 	}
-	return
+	return nil
+}
+
+// updateValidatorClientConfig writes client.toml of validator home so CLI commands against the home hit the node of
+// validator. chain id, keyring backend and RPC of validator as node are overridden by global client section then
+// by client section of validator.
+func (m *ChainBuilder) updateValidatorClientConfig(ic *types.IgniteConfig, i int, v *types.ValidatorConfig) (err error) {
+	strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_CLIENT)
+	conf := map[string]interface{}{
+		"chain-id":        m.option.ChainID,
+		"keyring-backend": m.option.KeyringBackend,
+		"node":            ic.GetValidatorRPC(v.Name),
+	}
+	defaults, _ := m.igniteConfigs["client"].(map[string]interface{})
+	for k, val := range defaults {
+		if _, ok := val.(map[string]interface{}); !ok {
+			conf[k] = val
+		}
+	}
+	vals, _ := m.igniteConfigs["validators"].([]interface{})
+	if i < len(vals) {
+		igniteSettings, _ := vals[i].(map[string]interface{})
+		settings, _ := igniteSettings["client"].(map[string]interface{})
+		for k, val := range settings {
+			conf[k] = val
+		}
+	}
+	log.Json("client config to update", conf)
+	if err = m.tomlFile(strPath).EditTOML(conf); err != nil {
+		return log.Errorf("update config [%s] error [%s]", strPath, err.Error())
+	}
+	return nil
 }
 
 func (m *ChainBuilder) mergeGenesisConfig(ic *types.IgniteConfig) (err error) {
 	for _, v := range ic.Validators {
-		vip := viper.New()
 		strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_GENESIS)
-		vip.SetConfigFile(strPath)
-		vip.SetConfigType("json")
-		if err = vip.ReadInConfig(); err != nil {
-			return log.Errorf("load config [%s] error [%s]", strPath, err.Error())
+		var genesis map[string]interface{}
+		if genesis, err = loadGenesisFile(strPath); err != nil {
+			return err
 		}
-		var genesis = make(map[string]interface{})
-		genesis = vip.AllSettings()
-
-		cf := confile.New(confile.DefaultJSONEncodingCreator, strPath)
-		if err = cf.Load(&genesis); err != nil {
+		if err = m.applyGenesisOverrides(ic, genesis); err != nil {
+			return err
+		}
+		var strHash string
+		if strHash, err = saveGenesisFile(strPath, genesis); err != nil {
 			return err
 		}
-		igniteSettings := m.igniteConfigs["genesis"]
+		log.Infof("[%s] genesis %s sha256 %s", v.Name, strPath, strHash)
+	}
+	return nil
+}
+
+// applyGenesisOverrides merges genesis settings of config file into genesis, then applies genesis mutators of
+// chain plugins enabled by config file
+func (m *ChainBuilder) applyGenesisOverrides(ic *types.IgniteConfig, genesis map[string]interface{}) (err error) {
+	if igniteSettings := m.igniteConfigs["genesis"]; igniteSettings != nil {
 		if err = mergo.Merge(&genesis, igniteSettings, mergo.WithOverride); err != nil {
 			return err
 		}
-		err = cf.SaveJSON(genesis)
+	}
+	if err = plugins.MutateGenesis(genesis, ic.Plugins); err != nil {
+		return log.Errorf("%s", err)
 	}
 	return nil
 }
@@ -415,6 +807,22 @@ func (m *ChainBuilder) syncGenesisFile(ic *types.IgniteConfig) (err error) {
 			}
 		}
 	}
+	//every validator must share the identical genesis file
+	var strHash string
+	for _, v := range ic.Validators {
+		strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_GENESIS)
+		var strFileHash string
+		if strFileHash, err = utils.FileSHA256(strPath); err != nil {
+			return log.Errorf("hash genesis %s error [%s]", strPath, err)
+		}
+		if strHash == "" {
+			strHash = strFileHash
+		} else if strFileHash != strHash {
+			return log.Errorf("validator [%s] genesis sha256 %s mismatch %s", v.Name, strFileHash, strHash)
+		}
+	}
+	m.report.GenesisHash = strHash
+	fmt.Printf("genesis sha256 %s\n", strHash)
 	return nil
 }
 
@@ -422,37 +830,45 @@ func (m *ChainBuilder) showValidators(ic *types.IgniteConfig) (err error) {
 	cmdline := ""
 	output := ""
 	opt := m.option
-	maker := m.maker
 	cmd := utils.NewCmdExecutor(opt.Debug)
 	var names, accAddrs, valAddrs []string
 	for _, v := range ic.Validators {
-		cmdline = maker.MakeCmdLineKeysShowAddrOnly(v.Home, v.Name, "acc")
+		cmdline = m.makerOf(&v).MakeCmdLineKeysShowAddrOnly(v.Home, v.Name, "acc")
 		output, err = cmd.Shell(cmdline)
 		if err != nil {
 			return log.Errorf(err.Error())
 		}
-		idx := strings.LastIndex(output, "\n")
-		if idx >= 0 {
-			output = output[idx+1:]
-		}
+		output = utils.LastLine(output)
 		log.Printf("validator acc addr [%s]", output)
 		names = append(names, v.Name)
 		accAddrs = append(accAddrs, output)
 	}
 	for _, v := range ic.Validators {
-		cmdline = maker.MakeCmdLineKeysShowAddrOnly(v.Home, v.Name, "val")
+		cmdline = m.makerOf(&v).MakeCmdLineKeysShowAddrOnly(v.Home, v.Name, "val")
 		output, err = cmd.Shell(cmdline)
 		if err != nil {
 			return log.Errorf(err.Error())
 		}
-		idx := strings.LastIndex(output, "\n")
-		if idx >= 0 {
-			output = output[idx+1:]
-		}
+		output = utils.LastLine(output)
 		log.Printf("validator val addr [%s]", output)
 		valAddrs = append(valAddrs, output)
 	}
 
+	for i, v := range ic.Validators {
+		vr := &types.ValidatorReport{
+			Name:    v.Name,
+			Home:    v.Home,
+			Moniker: v.Config.Moniker,
+			AccAddr: accAddrs[i],
+			ValAddr: valAddrs[i],
+			Binary:  m.makerOf(&ic.Validators[i]).NodeCmd(),
+			Version: m.versions[v.Name],
+		}
+		if np, ok := m.peers[v.Name]; ok {
+			vr.Peer = np.Peer
+		}
+		m.report.Validators = append(m.report.Validators, vr)
+	}
 	log.Printf("-----------------------------------------------------------------------")
 	for i, v := range accAddrs {
 		log.Printf("[%s] %s => %s", names[i], v, valAddrs[i])
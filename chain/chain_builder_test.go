@@ -0,0 +1,130 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeBinaries(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "build:\n  binary: go\n")
+	strOverlay := filepath.Join(root, "mixed.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator2\n  binary: gofmt\n"), 0o644))
+
+	opt := newTestOption(strConfig)
+	opt.NodeCmd = ""
+	opt.ConfigOverlays = []string{strOverlay}
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.Equal(t, "go", opt.NodeCmd)
+	require.Equal(t, "go", b.makerOf(&ic.Validators[0]).NodeCmd())
+	require.Equal(t, "gofmt", b.makerOf(&ic.Validators[1]).NodeCmd())
+
+	//gofmt has no version sub command
+	require.Error(t, b.preflight(ic))
+	ic.Validators[1].Binary = ""
+	require.NoError(t, b.preflight(ic))
+	require.Contains(t, b.versions["validator2"], "go version")
+
+	//node command flag wins
+	opt = newTestOption(strConfig)
+	b = NewChainBuilder(&opt).(*ChainBuilder)
+	_, err = b.parseConfig()
+	require.NoError(t, err)
+	require.Equal(t, types.DEFAULT_NODE_CMD, b.maker.NodeCmd())
+}
+
+func TestCheckValidatorSettings(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	strOverlay := filepath.Join(root, "settings.yml")
+	strSettings := "validators:\n- name: validator1\n  app:\n    api:\n      enable-unsafe-cors: true\n  config:\n    rpc:\n      max_body_bytes: \"1000\"\n"
+	require.NoError(t, os.WriteFile(strOverlay, []byte(strSettings), 0o644))
+
+	opt := newTestOption(strConfig)
+	opt.ConfigOverlays = []string{strOverlay}
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	//unknown key is a warning only, the string of int key is coerced
+	require.NoError(t, b.checkConfig(ic))
+	conf := b.igniteConfigs["validators"].([]interface{})[0].(map[string]interface{})["config"].(map[string]interface{})
+	require.Equal(t, int64(1000), conf["rpc"].(map[string]interface{})["max_body_bytes"])
+	//defaults of typed models fill keys absent in config file only
+	require.Equal(t, "1s", conf["consensus"].(map[string]interface{})["timeout_commit"])
+	require.Equal(t, "3s", conf["consensus"].(map[string]interface{})["timeout_propose"])
+	require.Equal(t, true, conf["p2p"].(map[string]interface{})["allow_duplicate_ip"])
+	app := b.igniteConfigs["validators"].([]interface{})[1].(map[string]interface{})["app"].(map[string]interface{})
+	require.Equal(t, "default", app["pruning"])
+	require.Equal(t, true, app["grpc"].(map[string]interface{})["enable"])
+	require.Equal(t, "sync", b.igniteConfigs["client"].(map[string]interface{})["broadcast-mode"])
+
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator1\n  config:\n    mempool:\n      size: lots\n"), 0o644))
+	ic, err = b.parseConfig()
+	require.NoError(t, err)
+	require.Error(t, b.checkConfig(ic))
+}
+
+func TestUpdateClientConfig(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "client:\n  broadcast-mode: block\n  openapi:\n    path: docs/static/openapi.yml\n")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	strOverlay := filepath.Join(root, "client.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator2\n  client:\n    output: json\n"), 0o644))
+	strClient1 := filepath.Join(home1, "config", types.FILE_NAME_CLIENT)
+	require.NoError(t, os.MkdirAll(filepath.Dir(strClient1), 0o755))
+	require.NoError(t, os.WriteFile(strClient1, []byte("# The network chain ID\nchain-id = \"\"\nnode = \"tcp://localhost:26657\"\n"), 0o644))
+
+	opt := newTestOption(strConfig)
+	opt.ConfigOverlays = []string{strOverlay}
+	opt.Backup = true
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, b.checkConfig(ic))
+	require.Equal(t, "block", ic.Client.BroadcastMode)
+	require.Equal(t, "json", ic.Validators[1].Client.Output)
+	require.NoError(t, b.updateClientConfig(ic))
+
+	data, err := os.ReadFile(strClient1)
+	require.NoError(t, err)
+	require.Equal(t, "# The network chain ID\nchain-id = \"test_9000-1\"\nnode = \"tcp://127.0.0.1:26657\"\nbroadcast-mode = \"block\"\nkeyring-backend = \"test\"\noutput = \"text\"\n", string(data))
+	//backup keeps the client.toml written by init
+	data, err = os.ReadFile(strClient1 + confile.BackupSuffix)
+	require.NoError(t, err)
+	require.Equal(t, "# The network chain ID\nchain-id = \"\"\nnode = \"tcp://localhost:26657\"\n", string(data))
+	require.NoFileExists(t, filepath.Join(home2, "config", types.FILE_NAME_CLIENT+confile.BackupSuffix))
+	data, err = os.ReadFile(filepath.Join(home2, "config", types.FILE_NAME_CLIENT))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "node = \"tcp://127.0.0.1:36657\"")
+	require.Contains(t, string(data), "output = \"json\"")
+}
+
+func TestPrepareHomes(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	require.NoError(t, os.MkdirAll(filepath.Join(home1, "config"), 0o755))
+	require.NoError(t, os.MkdirAll(home2, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home2, "notes.txt"), []byte("keep"), 0o600))
+
+	opt := newTestOption(strConfig)
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	//non-empty home without chain data is refused
+	require.Error(t, b.prepareHomes(ic))
+	require.FileExists(t, filepath.Join(home2, "notes.txt"))
+
+	//no allowed root falls back to workspace root
+	opt.AllowedRoots = nil
+	require.Error(t, b.prepareHomes(ic))
+	require.DirExists(t, filepath.Join(home1, "config"))
+
+	opt.AllowedRoots, opt.Force = []string{root}, true
+	require.NoError(t, b.prepareHomes(ic))
+	require.NoDirExists(t, home1)
+	require.NoDirExists(t, home2)
+}
@@ -0,0 +1,264 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/rpc"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layoutCosmovisor places genesis and upgrade binaries of config file into cosmovisor directory structure
+// of every validator home. eg. <home>/cosmovisor/genesis/bin/hobbyd, <home>/cosmovisor/upgrades/v2/bin/hobbyd
+// the genesis binary is the node binary of each validator unless cosmovisor.genesis is set.
+func (m *ChainBuilder) layoutCosmovisor(ic *types.IgniteConfig) (err error) {
+	cv := ic.Cosmovisor
+	if !cv.Enable {
+		return nil
+	}
+	var paths = make(map[string]string) //binary => absolute path
+	lookPath := func(strBin string) (string, error) {
+		if strPath, ok := paths[strBin]; ok {
+			return strPath, nil
+		}
+		strPath, err := exec.LookPath(strBin)
+		if err != nil {
+			return "", log.Errorf("cosmovisor binary %s not found [%s]", strBin, err)
+		}
+		paths[strBin] = strPath
+		return strPath, nil
+	}
+	for _, v := range ic.Validators {
+		strNodeCmd := m.makerOf(&v).NodeCmd()
+		strDaemon := filepath.Base(strNodeCmd)
+		strGenesisBin := cv.Genesis
+		if strGenesisBin == "" {
+			strGenesisBin = strNodeCmd
+		}
+		var bins = map[string]string{
+			types.COSMOVISOR_GENESIS_SUBPATH: strGenesisBin,
+		}
+		for strName, strBin := range cv.Upgrades {
+			bins[filepath.Join(types.COSMOVISOR_UPGRADES_SUBPATH, strName)] = strBin
+		}
+		for strSubPath, strBin := range bins {
+			var strSrc string
+			if strSrc, err = lookPath(strBin); err != nil {
+				return err
+			}
+			strDst := filepath.Join(v.Home, types.COSMOVISOR_SUBPATH, strSubPath, types.COSMOVISOR_BIN_SUBPATH, strDaemon)
+			if err = utils.CopyFile(strSrc, strDst, 0o755); err != nil {
+				return log.Errorf("copy cosmovisor binary %s to %s error [%s]", strSrc, strDst, err)
+			}
+			log.Debugf("[%s] %s => %s", v.Name, strSrc, strDst)
+		}
+		log.Infof("[%s] start with: DAEMON_NAME=%s DAEMON_HOME=%s cosmovisor run start --home %s", v.Name, strDaemon, v.Home, v.Home)
+	}
+	return nil
+}
+
+// UpgradeScheduler submits a software upgrade proposal, votes yes by every validator and watches heights
+// until the network halts at (or passes through by cosmovisor) the upgrade height.
+type UpgradeScheduler struct {
+	option   *types.UpgradeOption //upgrade option
+	builder  *ChainBuilder        //chain builder of network
+	interval time.Duration        //interval of polling heights
+	stall    time.Duration        //duration without new block at upgrade height regarded as halted
+}
+
+func NewUpgradeScheduler(opt *types.UpgradeOption) api.ManagerApi {
+	if opt == nil {
+		panic("upgrade option is nil")
+	}
+	return &UpgradeScheduler{
+		option:   opt,
+		builder:  NewChainBuilder(&opt.Option).(*ChainBuilder),
+		interval: types.UPGRADE_WATCH_INTERVAL * time.Second,
+		stall:    types.UPGRADE_HALT_STALL * time.Second,
+	}
+}
+
+func (m *UpgradeScheduler) Run() (err error) {
+	if err = m.builder.openNetwork(); err != nil {
+		return err
+	}
+	err = m.schedule()
+	m.builder.journal(types.JOURNAL_CMD_UPGRADE_SCHEDULE, m.option.Name, err)
+	return err
+}
+
+func (m *UpgradeScheduler) schedule() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	b.probeDialects(ic)
+	if opt.Node == "" {
+		opt.Node = ic.GetValidatorRPC(b.strNode0Validator)
+	}
+	if opt.From == "" {
+		opt.From = b.strNode0Validator
+	}
+	if opt.Deposit == "" {
+		if opt.Deposit = m.minDeposit(); opt.Deposit == "" {
+			return log.Errorf("proposal deposit is empty and no gov min_deposit found in config file")
+		}
+	}
+	cli := rpc.NewClient(opt.Node)
+	status, err := cli.Status(context.Background())
+	if err != nil {
+		return log.Errorf("query node %s status error [%s]", opt.Node, err)
+	}
+	if opt.Height <= status.SyncInfo.LatestBlockHeight {
+		return log.Errorf("upgrade height %d must be greater than current height %d", opt.Height, status.SyncInfo.LatestBlockHeight)
+	}
+	var strProposalID string
+	if strProposalID, err = m.submitProposal(); err != nil {
+		return err
+	}
+	for _, v := range ic.Validators {
+		cmdline := b.makerOf(&v).MakeCmdLineTxGovVote(strProposalID, types.VOTE_OPTION_YES, v.Name, v.Home, opt.Node, opt.Fees)
+		var output, strTxHash string
+		if output, err = utils.NewCmdExecutor(opt.Debug).Shell(cmdline); err != nil {
+			return log.Errorf("validator [%s] vote proposal %s error [%s]", v.Name, strProposalID, err)
+		}
+		if strTxHash, err = parseTxResponse(output); err != nil {
+			return log.Errorf("validator [%s] vote proposal %s error [%s]", v.Name, strProposalID, err)
+		}
+		log.Infof("validator [%s] voted yes on proposal %s tx [%s]", v.Name, strProposalID, strTxHash)
+	}
+	fmt.Printf("upgrade %s scheduled at height %d by proposal %s\n", opt.Name, opt.Height, strProposalID)
+	if opt.NoWatch {
+		return nil
+	}
+	return m.watch(cli)
+}
+
+// submitProposal submits software upgrade proposal and waits until it is included in a block
+func (m *UpgradeScheduler) submitProposal() (strProposalID string, err error) {
+	b := m.builder
+	opt := m.option
+	cmd := utils.NewCmdExecutor(opt.Debug)
+	var nLastID uint64
+	if nLastID, err = m.latestProposalID(); err != nil {
+		return "", err
+	}
+	strTitle := fmt.Sprintf(types.DEFAULT_UPGRADE_TITLE, opt.Name)
+	cmdline := b.maker.MakeCmdLineTxSoftwareUpgrade(opt.Name, opt.Height, opt.Info, strTitle, opt.Deposit, opt.From, b.strNode0Home, opt.Node, opt.Fees)
+	var output, strTxHash string
+	if output, err = cmd.Shell(cmdline); err != nil {
+		return "", log.Errorf("submit upgrade proposal error [%s]", err)
+	}
+	if strTxHash, err = parseTxResponse(output); err != nil {
+		return "", log.Errorf("submit upgrade proposal error [%s]", err)
+	}
+	log.Infof("upgrade proposal submitted tx [%s]", strTxHash)
+	deadline := time.Now().Add(types.QUERY_POLL_TIMEOUT * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(types.QUERY_POLL_INTERVAL * time.Second)
+		var nID uint64
+		if nID, err = m.latestProposalID(); err == nil && nID > nLastID {
+			return strconv.FormatUint(nID, 10), nil
+		}
+	}
+	return "", log.Errorf("upgrade proposal not found after %d seconds", types.QUERY_POLL_TIMEOUT)
+}
+
+// latestProposalID returns the max proposal id on chain, 0 if no proposal
+func (m *UpgradeScheduler) latestProposalID() (nID uint64, err error) {
+	var proposals struct {
+		Proposals []struct {
+			ID         string `json:"id"`
+			ProposalID string `json:"proposal_id"`
+		} `json:"proposals"`
+	}
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	output, err := cmd.Shell(m.builder.maker.MakeCmdLineQueryProposals(m.option.Node))
+	if err != nil {
+		if strings.Contains(strings.ToLower(output), "no proposals found") {
+			//no proposal on chain yet
+			return 0, nil
+		}
+		return 0, log.Errorf("query proposals error [%s] %s", err, utils.LastLine(output))
+	}
+	if err = json.Unmarshal([]byte(utils.LastLine(output)), &proposals); err != nil {
+		return 0, log.Errorf("unmarshal proposals error [%s]", err)
+	}
+	for _, p := range proposals.Proposals {
+		strID := p.ID
+		if strID == "" {
+			strID = p.ProposalID
+		}
+		if n, err := strconv.ParseUint(strID, 10, 64); err == nil && n > nID {
+			nID = n
+		}
+	}
+	return nID, nil
+}
+
+// watch polls node height until upgrade height passed (cosmovisor switched binary) or network halted
+// at upgrade height
+func (m *UpgradeScheduler) watch(cli *rpc.Client) error {
+	opt := m.option
+	var nLastHeight int64
+	var lastChanged = time.Now()
+	var deadline time.Time
+	if opt.Timeout > 0 {
+		deadline = time.Now().Add(opt.Timeout)
+	}
+	for {
+		status, err := cli.Status(context.Background())
+		if err == nil {
+			nHeight := status.SyncInfo.LatestBlockHeight
+			if nHeight != nLastHeight {
+				nLastHeight, lastChanged = nHeight, time.Now()
+				log.Infof("height %d / upgrade height %d", nHeight, opt.Height)
+			}
+			if nHeight > opt.Height {
+				fmt.Printf("network passed upgrade height %d, upgrade %s applied\n", opt.Height, opt.Name)
+				return nil
+			}
+		}
+		if nLastHeight >= opt.Height-1 && time.Since(lastChanged) > m.stall {
+			fmt.Printf("network halted at height %d for upgrade %s, restart nodes with the new binary\n", nLastHeight, opt.Name)
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return log.Errorf("watch upgrade timeout at height %d", nLastHeight)
+		}
+		time.Sleep(m.interval)
+	}
+}
+
+// minDeposit returns gov min_deposit of genesis in config file as coins string. eg. 10000usby,10000uhby
+func (m *UpgradeScheduler) minDeposit() string {
+	var coins []string
+	genesis, _ := m.builder.igniteConfigs["genesis"].(map[string]interface{})
+	appState, _ := genesis["app_state"].(map[string]interface{})
+	gov, _ := appState["gov"].(map[string]interface{})
+	params, _ := gov["params"].(map[string]interface{})
+	deposits, _ := params["min_deposit"].([]interface{})
+	for _, d := range deposits {
+		dm, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		coins = append(coins, fmt.Sprintf("%v%v", dm["amount"], dm["denom"]))
+	}
+	sort.Strings(coins)
+	return strings.Join(coins, ",")
+}
@@ -0,0 +1,117 @@
+package chain
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/civet148/cosmos-cli/rpc"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutCosmovisor(t *testing.T) {
+	strBinDir := t.TempDir()
+	var bins = make(map[string]string)
+	for _, strName := range []string{"hobbyd", "hobbyd-v1.1", "hobbyd-v2"} {
+		bins[strName] = filepath.Join(strBinDir, strName)
+		require.NoError(t, os.WriteFile(bins[strName], []byte("#!/bin/sh\necho "+strName+"\n"), 0o755))
+	}
+	root, strConfig := writeTestConfig(t, fmt.Sprintf("cosmovisor:\n  enable: true\n  upgrades:\n    v2: %s\n", bins["hobbyd-v2"]))
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	strOverlay := filepath.Join(root, "binary.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator2\n  binary: "+bins["hobbyd-v1.1"]+"\n"), 0o644))
+
+	opt := newTestOption(strConfig)
+	opt.NodeCmd = bins["hobbyd"]
+	opt.ConfigOverlays = []string{strOverlay}
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, b.checkConfig(ic))
+	require.NoError(t, b.layoutCosmovisor(ic))
+
+	readBin := func(strPath string) string {
+		data, err := os.ReadFile(strPath)
+		require.NoError(t, err)
+		return string(data)
+	}
+	//genesis binary is the node binary of each validator, named by its daemon name
+	require.Contains(t, readBin(filepath.Join(home1, "cosmovisor", "genesis", "bin", "hobbyd")), "echo hobbyd\n")
+	require.Contains(t, readBin(filepath.Join(home2, "cosmovisor", "genesis", "bin", "hobbyd-v1.1")), "echo hobbyd-v1.1\n")
+	require.Contains(t, readBin(filepath.Join(home1, "cosmovisor", "upgrades", "v2", "bin", "hobbyd")), "echo hobbyd-v2\n")
+	require.Contains(t, readBin(filepath.Join(home2, "cosmovisor", "upgrades", "v2", "bin", "hobbyd-v1.1")), "echo hobbyd-v2\n")
+	fi, err := os.Stat(filepath.Join(home1, "cosmovisor", "genesis", "bin", "hobbyd"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+	//cosmovisor.genesis wins over node binaries
+	ic.Cosmovisor.Genesis = bins["hobbyd-v2"]
+	require.NoError(t, b.layoutCosmovisor(ic))
+	require.Contains(t, readBin(filepath.Join(home2, "cosmovisor", "genesis", "bin", "hobbyd-v1.1")), "echo hobbyd-v2\n")
+
+	ic.Cosmovisor.Upgrades["v3"] = filepath.Join(strBinDir, "missing")
+	require.Error(t, b.layoutCosmovisor(ic))
+}
+
+func TestUpgradeSchedulerWatch(t *testing.T) {
+	f := &fakeRPC{address: "ADDR1", height: 8}
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	newScheduler := func(timeout time.Duration) *UpgradeScheduler {
+		return &UpgradeScheduler{
+			option:   &types.UpgradeOption{Name: "v2", Height: 10, Timeout: timeout},
+			interval: time.Millisecond,
+			stall:    50 * time.Millisecond,
+		}
+	}
+	cli := rpc.NewClient(ts.URL)
+
+	//cosmovisor switched binary and the network passed upgrade height
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		f.set(func(f *fakeRPC) { f.height = 11 })
+	}()
+	require.NoError(t, newScheduler(time.Second).watch(cli))
+
+	//no new block at upgrade height is regarded as halted for upgrade
+	f.set(func(f *fakeRPC) { f.height = 10 })
+	start := time.Now()
+	require.NoError(t, newScheduler(time.Second).watch(cli))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	//a network stalled below upgrade height is not halted for upgrade and times out
+	f.set(func(f *fakeRPC) { f.height = 5 })
+	err := newScheduler(200 * time.Millisecond).watch(cli)
+	require.ErrorContains(t, err, "watch upgrade timeout at height 5")
+
+	//unreachable node times out too
+	ts.Close()
+	err = newScheduler(100 * time.Millisecond).watch(cli)
+	require.ErrorContains(t, err, "watch upgrade timeout at height 0")
+}
+
+func TestLatestProposalID(t *testing.T) {
+	strBin := filepath.Join(t.TempDir(), "hobbyd")
+	newScheduler := func(strScript string) *UpgradeScheduler {
+		require.NoError(t, os.WriteFile(strBin, []byte("#!/bin/sh\n"+strScript), 0o755))
+		opt := &types.UpgradeOption{Option: newTestOption(""), Node: "tcp://127.0.0.1:26657"}
+		opt.NodeCmd = strBin
+		return &UpgradeScheduler{option: opt, builder: NewChainBuilder(&opt.Option).(*ChainBuilder)}
+	}
+	nID, err := newScheduler(`echo '{"proposals":[{"id":"3"},{"proposal_id":"7"}]}'` + "\n").latestProposalID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nID)
+
+	//no proposal on chain yet
+	nID, err = newScheduler("echo 'Error: rpc error: code = NotFound desc = no proposals found'\nexit 1\n").latestProposalID()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), nID)
+
+	//other query errors are not regarded as no proposal
+	_, err = newScheduler("echo 'Error: post failed: connection refused'\nexit 1\n").latestProposalID()
+	require.ErrorContains(t, err, "connection refused")
+}
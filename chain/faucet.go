@@ -0,0 +1,338 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/shells"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+var (
+	coinRegexp = regexp.MustCompile(`^([0-9]+)([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+)
+
+// FaucetSender sends coins from faucet account and queries balances, it is replaced by a fake in tests
+type FaucetSender interface {
+	// Balances returns balances of address by denom
+	Balances(strAddr string) (map[string]*big.Int, error)
+	// Send sends coins to address and returns transaction hash
+	Send(strAddr string, coins []string) (string, error)
+}
+
+// Faucet serves a JSON API sending configured coins from an account of config file to requesting addresses
+type Faucet struct {
+	option  *types.FaucetOption //faucet option
+	builder *ChainBuilder       //chain builder of network
+}
+
+func NewFaucet(opt *types.FaucetOption) api.ManagerApi {
+	if opt == nil {
+		panic("faucet option is nil")
+	}
+	return &Faucet{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+}
+
+func (m *Faucet) Run() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	b.probeDialects(ic)
+	fc := ic.Faucet
+	if fc.Name == "" || len(fc.Coins) == 0 {
+		return log.Errorf("faucet name and coins must not be empty in config file %s", opt.ConfigPath)
+	}
+	if ic.GetAccountCoins(fc.Name) == nil {
+		return log.Errorf("faucet account [%s] not found in accounts of config file %s", fc.Name, opt.ConfigPath)
+	}
+	if opt.Node == "" {
+		opt.Node = ic.GetValidatorRPC(b.strNode0Validator)
+	}
+	sender := &cliFaucetSender{
+		option:       opt,
+		builder:      b,
+		maker:        b.makerOf(&ic.Validators[0]),
+		strName:      fc.Name,
+		strGasPrices: pickGasPrice(ic.Validators[0].App.MinimumGasPrices, opt.DefaultDenom),
+	}
+	var strAddr string
+	if strAddr, err = sender.address(); err != nil {
+		return err
+	}
+	var server *FaucetServer
+	if server, err = NewFaucetServer(sender, strAddr, fc.Coins, fc.CoinsMax, fc.RateLimitWindow); err != nil {
+		return err
+	}
+	strListen := opt.Listen
+	if strListen == "" {
+		strListen = fc.Host
+	}
+	if strListen == "" {
+		strListen = types.FAUCET_DEFAULT_LISTEN
+	}
+	fmt.Printf("faucet of account [%s] %s sending %s listening on %s\n", fc.Name, strAddr, strings.Join(fc.Coins, ","), strListen)
+	return http.ListenAndServe(strListen, server)
+}
+
+// FaucetServer is HTTP handler of faucet JSON API.
+//
+//	GET  /  faucet information
+//	POST /  {"address":"hby1..."} sends coins to address
+type FaucetServer struct {
+	sender   FaucetSender
+	strAddr  string              //faucet address
+	coins    []string            //coins sent per request
+	amounts  map[string]*big.Int //amounts of coins by denom
+	maxes    map[string]*big.Int //max balance of address by denom
+	window   time.Duration       //min interval of requests per address
+	locker   sync.Mutex          //serializes sending (account sequence) and guards credits
+	credited map[string]time.Time
+	now      func() time.Time
+}
+
+type faucetRequest struct {
+	Address string `json:"address"`
+}
+
+type faucetResponse struct {
+	Address string   `json:"address,omitempty"`
+	Coins   []string `json:"coins,omitempty"`
+	TxHash  string   `json:"txhash,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type faucetInfo struct {
+	Address         string   `json:"address"`
+	Coins           []string `json:"coins"`
+	CoinsMax        []string `json:"coins_max"`
+	RateLimitWindow string   `json:"rate_limit_window"`
+}
+
+func NewFaucetServer(sender FaucetSender, strAddr string, coins, coinsMax []string, strWindow string) (*FaucetServer, error) {
+	if strWindow == "" {
+		strWindow = types.FAUCET_DEFAULT_RATE_WINDOW
+	}
+	window, err := time.ParseDuration(strWindow)
+	if err != nil {
+		return nil, log.Errorf("invalid faucet rate limit window %s", strWindow)
+	}
+	s := &FaucetServer{
+		sender:   sender,
+		strAddr:  strAddr,
+		coins:    coins,
+		window:   window,
+		credited: make(map[string]time.Time),
+		now:      time.Now,
+	}
+	if s.amounts, err = parseCoinAmounts(coins); err != nil {
+		return nil, err
+	}
+	if s.maxes, err = parseCoinAmounts(coinsMax); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FaucetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var maxes []string
+		for strDenom, amount := range s.maxes {
+			maxes = append(maxes, amount.String()+strDenom)
+		}
+		sort.Strings(maxes)
+		writeFaucetJSON(w, http.StatusOK, &faucetInfo{
+			Address:         s.strAddr,
+			Coins:           s.coins,
+			CoinsMax:        maxes,
+			RateLimitWindow: s.window.String(),
+		})
+	case http.MethodPost:
+		var req faucetRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeFaucetJSON(w, http.StatusBadRequest, &faucetResponse{Error: "invalid request body"})
+			return
+		}
+		status, resp := s.credit(strings.TrimSpace(req.Address))
+		writeFaucetJSON(w, status, resp)
+	default:
+		writeFaucetJSON(w, http.StatusMethodNotAllowed, &faucetResponse{Error: "method not allowed"})
+	}
+}
+
+// credit sends coins to address unless it was credited within rate limit window, denoms whose balance would exceed
+// max balance are left out
+func (s *FaucetServer) credit(strAddr string) (int, *faucetResponse) {
+	resp := &faucetResponse{Address: strAddr}
+	if err := s.checkAddress(strAddr); err != nil {
+		resp.Error = err.Error()
+		return http.StatusBadRequest, resp
+	}
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if last, ok := s.credited[strAddr]; ok && s.now().Sub(last) < s.window {
+		resp.Error = fmt.Sprintf("address was credited at %s, retry after %s", last.Format(types.TIME_FORMAT_DISPLAY), last.Add(s.window).Format(types.TIME_FORMAT_DISPLAY))
+		return http.StatusTooManyRequests, resp
+	}
+	var coins []string
+	if len(s.maxes) == 0 {
+		coins = s.coins
+	} else {
+		balances, err := s.sender.Balances(strAddr)
+		if err != nil {
+			resp.Error = fmt.Sprintf("query balances error: %s", err)
+			return http.StatusInternalServerError, resp
+		}
+		for _, strCoin := range s.coins {
+			strDenom := coinRegexp.FindStringSubmatch(strCoin)[2]
+			max, ok := s.maxes[strDenom]
+			if !ok {
+				coins = append(coins, strCoin)
+				continue
+			}
+			balance := balances[strDenom]
+			if balance == nil {
+				balance = new(big.Int)
+			}
+			if new(big.Int).Add(balance, s.amounts[strDenom]).Cmp(max) <= 0 {
+				coins = append(coins, strCoin)
+			}
+		}
+	}
+	if len(coins) == 0 {
+		resp.Error = "address reached max balance"
+		return http.StatusForbidden, resp
+	}
+	strTxHash, err := s.sender.Send(strAddr, coins)
+	if err != nil {
+		resp.Error = fmt.Sprintf("send coins error: %s", err)
+		return http.StatusInternalServerError, resp
+	}
+	s.credited[strAddr] = s.now()
+	resp.Coins, resp.TxHash = coins, strTxHash
+	log.Infof("faucet sent %s to %s tx %s", strings.Join(coins, ","), strAddr, strTxHash)
+	return http.StatusOK, resp
+}
+
+// checkAddress checks address is bech32 account address of the same prefix as faucet address
+func (s *FaucetServer) checkAddress(strAddr string) error {
+	strPrefix, _, err := bech32.DecodeAndConvert(strAddr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q", strAddr)
+	}
+	if strFaucetPrefix, _, err := bech32.DecodeAndConvert(s.strAddr); err == nil && strPrefix != strFaucetPrefix {
+		return fmt.Errorf("address prefix %s is not %s", strPrefix, strFaucetPrefix)
+	}
+	return nil
+}
+
+func writeFaucetJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// parseCoinAmounts parses coins (eg. 10uhby) into amounts by denom
+func parseCoinAmounts(coins []string) (map[string]*big.Int, error) {
+	var amounts = make(map[string]*big.Int)
+	for _, strCoin := range coins {
+		sub := coinRegexp.FindStringSubmatch(strings.TrimSpace(strCoin))
+		if sub == nil {
+			return nil, log.Errorf("invalid coin %s", strCoin)
+		}
+		amount, _ := new(big.Int).SetString(sub[1], 10)
+		amounts[sub[2]] = amount
+	}
+	return amounts, nil
+}
+
+// cliFaucetSender sends coins by tx and query commands of node binary
+type cliFaucetSender struct {
+	option       *types.FaucetOption
+	builder      *ChainBuilder
+	maker        *shells.ChainMaker //chain maker of first validator whose keyring holds faucet account
+	strName      string             //faucet account name
+	strGasPrices string             //gas prices
+}
+
+func (s *cliFaucetSender) address() (string, error) {
+	b := s.builder
+	output, err := utils.NewCmdExecutor(s.option.Debug).Shell(s.maker.MakeCmdLineKeysShowAddrOnly(b.strNode0Home, s.strName, "acc"))
+	if err != nil {
+		return "", log.Errorf("show address of faucet account [%s] error [%s]", s.strName, err)
+	}
+	return utils.LastLine(output), nil
+}
+
+func (s *cliFaucetSender) Balances(strAddr string) (map[string]*big.Int, error) {
+	var balances struct {
+		Balances []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balances"`
+	}
+	output, err := utils.NewCmdExecutor(s.option.Debug).Shell(s.maker.MakeCmdLineQueryBalances(strAddr, s.option.Node))
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(utils.LastLine(output)), &balances); err != nil {
+		return nil, err
+	}
+	var amounts = make(map[string]*big.Int)
+	for _, c := range balances.Balances {
+		if amount, ok := new(big.Int).SetString(c.Amount, 10); ok {
+			amounts[c.Denom] = amount
+		}
+	}
+	return amounts, nil
+}
+
+// Send broadcasts bank send transaction and waits until it is committed, so the next transaction of faucet account
+// is signed by the committed account sequence
+func (s *cliFaucetSender) Send(strAddr string, coins []string) (strTxHash string, err error) {
+	b := s.builder
+	cmd := utils.NewCmdExecutor(s.option.Debug)
+	maker := s.maker.WithGasPrices(s.strGasPrices)
+	var output string
+	if output, err = cmd.Shell(maker.MakeCmdLineTxBankSend(s.strName, strAddr, strings.Join(coins, ","), b.strNode0Home, s.option.Node, "")); err != nil {
+		return "", err
+	}
+	if strTxHash, err = parseTxResponse(output); err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(types.QUERY_POLL_TIMEOUT * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(types.QUERY_POLL_INTERVAL * time.Second)
+		//query fails until the transaction is committed
+		if output, err = cmd.Shell(maker.MakeCmdLineQueryTx(strTxHash, s.option.Node)); err == nil {
+			if _, err = parseTxResponse(output); err != nil {
+				return "", err
+			}
+			return strTxHash, nil
+		}
+	}
+	return "", fmt.Errorf("tx %s not committed after %d seconds", strTxHash, types.QUERY_POLL_TIMEOUT)
+}
@@ -0,0 +1,163 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/civet148/cosmos-cli/shells"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFaucetSender struct {
+	balances map[string]*big.Int
+	sent     [][]string
+	err      error
+}
+
+func (s *fakeFaucetSender) Balances(strAddr string) (map[string]*big.Int, error) {
+	return s.balances, nil
+}
+
+func (s *fakeFaucetSender) Send(strAddr string, coins []string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.sent = append(s.sent, coins)
+	return strings.Repeat("AB", 32), nil
+}
+
+func testBech32(t *testing.T, strPrefix string, b byte) string {
+	strAddr, err := bech32.ConvertAndEncode(strPrefix, append(make([]byte, 19), b))
+	require.NoError(t, err)
+	return strAddr
+}
+
+func postFaucet(t *testing.T, url, strBody string) (int, map[string]interface{}) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(strBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var m map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&m))
+	return resp.StatusCode, m
+}
+
+func TestFaucetServer(t *testing.T) {
+	sender := &fakeFaucetSender{balances: map[string]*big.Int{}}
+	strFaucet := testBech32(t, "hby", 0)
+	s, err := NewFaucetServer(sender, strFaucet, []string{"10uhby", "5usby"}, []string{"25uhby"}, "1h")
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	var info faucetInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	resp.Body.Close()
+	require.Equal(t, faucetInfo{Address: strFaucet, Coins: []string{"10uhby", "5usby"}, CoinsMax: []string{"25uhby"}, RateLimitWindow: "1h0m0s"}, info)
+
+	strAddr := testBech32(t, "hby", 1)
+	status, m := postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, strAddr))
+	require.Equal(t, http.StatusOK, status, m)
+	require.Equal(t, strings.Repeat("AB", 32), m["txhash"])
+	require.Equal(t, [][]string{{"10uhby", "5usby"}}, sender.sent)
+
+	//rate limited within window
+	status, m = postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, strAddr))
+	require.Equal(t, http.StatusTooManyRequests, status)
+	require.Contains(t, m["error"], "retry after 2024-01-01 01:00:00")
+
+	//max balance guard leaves out uhby, then refuses when nothing is left
+	now = now.Add(time.Hour)
+	sender.balances["uhby"] = big.NewInt(20)
+	status, m = postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, strAddr))
+	require.Equal(t, http.StatusOK, status, m)
+	require.Equal(t, []string{"5usby"}, sender.sent[1])
+	s2, err := NewFaucetServer(sender, strFaucet, []string{"10uhby"}, []string{"25uhby"}, "")
+	require.NoError(t, err)
+	status, resp2 := s2.credit(strAddr)
+	require.Equal(t, http.StatusForbidden, status)
+	require.Equal(t, "address reached max balance", resp2.Error)
+
+	//bad requests
+	status, m = postFaucet(t, ts.URL, `{"address":"hby1invalid"}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, m["error"], "invalid address")
+	status, m = postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, testBech32(t, "cosmos", 1)))
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "address prefix cosmos is not hby", m["error"])
+	status, _ = postFaucet(t, ts.URL, `not json`)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	//send failure is not rate limited
+	sender.err = fmt.Errorf("account sequence mismatch")
+	strAddr3 := testBech32(t, "hby", 3)
+	status, m = postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, strAddr3))
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Contains(t, m["error"], "account sequence mismatch")
+	sender.err = nil
+	status, _ = postFaucet(t, ts.URL, fmt.Sprintf(`{"address":%q}`, strAddr3))
+	require.Equal(t, http.StatusOK, status)
+}
+
+func TestParseCoinAmounts(t *testing.T) {
+	amounts, err := parseCoinAmounts([]string{"400000000000000000000000uhby", " 5ibc/ABC "})
+	require.NoError(t, err)
+	require.Equal(t, "400000000000000000000000", amounts["uhby"].String())
+	require.Equal(t, "5", amounts["ibc/ABC"].String())
+	_, err = parseCoinAmounts([]string{"uhby10"})
+	require.Error(t, err)
+	_, err = NewFaucetServer(nil, "", []string{"1uhby"}, nil, "daily")
+	require.Error(t, err)
+}
+
+const testFaucetNodeCmd = `#!/bin/sh
+HASH=A1B2C3D4E5F60718293A4B5C6D7E8F90A1B2C3D4E5F60718293A4B5C6D7E8F90
+case "$1 $2" in
+"tx bank")
+	echo "gas estimate: 81234"
+	if [ "$6" = "13uhby" ]; then
+		echo '{"txhash":"'$HASH'","codespace":"sdk","code":13,"raw_log":"insufficient fee"}'
+	else
+		echo '{"txhash":"'$HASH'","code":0,"raw_log":"[]"}'
+	fi;;
+"query tx")
+	echo '{"height":"7","txhash":"'$HASH'","code":0}';;
+*)
+	exit 1;;
+esac
+`
+
+func TestCliFaucetSender(t *testing.T) {
+	root := t.TempDir()
+	strNodeCmd := filepath.Join(root, "faucetd")
+	require.NoError(t, os.WriteFile(strNodeCmd, []byte(testFaucetNodeCmd), 0o755))
+	opt := &types.FaucetOption{Option: newTestOption(filepath.Join(root, "config.yml")), Node: "tcp://127.0.0.1:26657"}
+	b := NewChainBuilder(&opt.Option).(*ChainBuilder)
+	s := &cliFaucetSender{
+		option:  opt,
+		builder: b,
+		maker:   shells.NewChainMaker(strNodeCmd, "test_9000-1", "uhby", types.DEFAULT_KEY_PHRASE, types.KEYRING_BACKEND_TEST),
+		strName: "faucet",
+	}
+	//rejected by CheckTx although the command exits 0
+	_, err := s.Send("hby1qqqq", []string{"13uhby"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "insufficient fee")
+
+	strTxHash, err := s.Send("hby1qqqq", []string{"10uhby"})
+	require.NoError(t, err)
+	require.Equal(t, "A1B2C3D4E5F60718293A4B5C6D7E8F90A1B2C3D4E5F60718293A4B5C6D7E8F90", strTxHash)
+}
@@ -0,0 +1,33 @@
+package chain
+
+import (
+	"encoding/json"
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"os"
+)
+
+// loadGenesisFile decodes genesis file keeping numbers as json.Number to avoid precision loss
+func loadGenesisFile(strPath string) (genesis map[string]interface{}, err error) {
+	var file *os.File
+	if file, err = os.Open(strPath); err != nil {
+		return nil, log.Errorf("open genesis %s error [%s]", strPath, err)
+	}
+	defer file.Close()
+	dec := json.NewDecoder(file)
+	dec.UseNumber()
+	if err = dec.Decode(&genesis); err != nil {
+		return nil, log.Errorf("decode genesis %s error [%s]", strPath, err)
+	}
+	return genesis, nil
+}
+
+// saveGenesisFile writes genesis as canonical JSON and returns SHA-256 of the file content
+func saveGenesisFile(strPath string, genesis map[string]interface{}) (strHash string, err error) {
+	var data []byte
+	if data, err = confile.NewFile(strPath).SaveCanonicalJSON(genesis); err != nil {
+		return "", log.Errorf("save genesis %s error [%s]", strPath, err)
+	}
+	return utils.BytesSHA256(data), nil
+}
@@ -0,0 +1,130 @@
+package chain
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/shells"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"time"
+)
+
+// GenesisMigrator exports state of a stopped network and regenerates genesis of a new chain from it
+// for software upgrade rehearsal, optionally with a new chain node binary.
+type GenesisMigrator struct {
+	option  *types.GenesisOption //genesis option
+	builder *ChainBuilder        //chain builder of network
+}
+
+func NewGenesisMigrator(opt *types.GenesisOption) api.ManagerApi {
+	if opt == nil {
+		panic("genesis option is nil")
+	}
+	return &GenesisMigrator{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+}
+
+func (m *GenesisMigrator) Run() (err error) {
+	if err = m.builder.openNetwork(); err != nil {
+		return err
+	}
+	err = m.migrate()
+	m.builder.journal(types.JOURNAL_CMD_GENESIS_MIGRATE, types.STAGE_MERGE_GENESIS, err)
+	return err
+}
+
+func (m *GenesisMigrator) migrate() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	b.probeDialects(ic)
+	cmd := utils.NewCmdExecutor(opt.Debug)
+
+	//export state from node0 with current binary
+	strExported := utils.MakeCosmosConfigPath(b.strNode0Home, types.FILE_NAME_EXPORTED_GENESIS)
+	if _, err = cmd.Shell(b.makerOf(&ic.Validators[0]).MakeCmdLineExport(b.strNode0Home, strExported, opt.ExportHeight)); err != nil {
+		return log.Errorf("export state from %s error [%s] (is the node stopped?)", b.strNode0Home, err)
+	}
+	var genesis map[string]interface{}
+	if genesis, err = loadGenesisFile(strExported); err != nil {
+		return err
+	}
+	var strChainID string
+	if strChainID, err = m.rewriteGenesis(genesis, ic); err != nil {
+		return err
+	}
+
+	//all commands of the new chain run by the new binary
+	strNodeCmd := opt.NewNodeCmd
+	if strNodeCmd == "" {
+		strNodeCmd = opt.NodeCmd
+	}
+	maker := shells.NewChainMaker(strNodeCmd, strChainID, opt.DefaultDenom, opt.KeyPhrase, opt.KeyringBackend)
+	maker = maker.WithDialect(b.dialectOf(maker))
+	for _, v := range ic.Validators {
+		strPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_GENESIS)
+		var strHash string
+		if strHash, err = saveGenesisFile(strPath, genesis); err != nil {
+			return err
+		}
+		strClient := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_CLIENT)
+		if err = confile.New(confile.DefaultTOMLEncodingCreator, strClient).EditTOML(map[string]interface{}{"chain-id": strChainID}); err != nil {
+			return log.Errorf("update config [%s] error [%s]", strClient, err.Error())
+		}
+		if !opt.NoReset {
+			if _, err = cmd.Shell(maker.MakeCmdLineUnsafeResetAll(v.Home)); err != nil {
+				return log.Errorf(err.Error())
+			}
+		}
+		log.Infof("validator [%s] genesis migrated to %s sha256 %s", v.Name, strPath, strHash)
+	}
+	if _, err = cmd.Shell(maker.MakeCmdLineValidateGenesis(b.strNode0Home)); err != nil {
+		return log.Errorf("validate migrated genesis by %s error [%s]", strNodeCmd, err)
+	}
+	if strChainID != ic.Genesis.ChainID {
+		log.Warnf("chain id changed to %s, please update genesis.chain_id of config file %s", strChainID, opt.ConfigPath)
+	}
+	fmt.Printf("genesis migrated to chain %s, start nodes with %s\n", strChainID, strNodeCmd)
+	return nil
+}
+
+// rewriteGenesis applies genesis overrides of config file to exported genesis and sets chain_id, initial_height
+// and genesis_time of the new chain after them. config file overrides of these keys are superseded because the
+// exported state and flags decide them.
+func (m *GenesisMigrator) rewriteGenesis(genesis map[string]interface{}, ic *types.IgniteConfig) (strChainID string, err error) {
+	opt := m.option
+	//export writes initial_height as exported height + 1
+	exportedHeight := genesis["initial_height"]
+	if err = m.builder.applyGenesisOverrides(ic, genesis); err != nil {
+		return "", err
+	}
+	strChainID = opt.NewChainID
+	if strChainID == "" {
+		strChainID = ic.Genesis.ChainID
+	}
+	genesis["chain_id"] = strChainID
+	if opt.InitialHeight != "" {
+		genesis["initial_height"] = opt.InitialHeight
+	} else if exportedHeight != nil {
+		genesis["initial_height"] = exportedHeight
+	}
+	strGenesisTime := opt.GenesisTime
+	if strGenesisTime == "" {
+		strGenesisTime = time.Now().UTC().Format(time.RFC3339Nano)
+	} else if _, err = time.Parse(time.RFC3339Nano, strGenesisTime); err != nil {
+		return "", log.Errorf("genesis time %s is not RFC3339 format", strGenesisTime)
+	}
+	genesis["genesis_time"] = strGenesisTime
+	log.Infof("new chain id %s initial height %v genesis time %s", strChainID, genesis["initial_height"], strGenesisTime)
+	return strChainID, nil
+}
@@ -0,0 +1,53 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testExportedGenesis is genesis exported at height 120
+const testExportedGenesis = `{"genesis_time":"2023-11-09T07:46:56Z","chain_id":"test_9000-1","initial_height":"121",
+"app_state":{"bank":{"balances":[{"address":"hby1","coins":[{"denom":"uhby","amount":"400000000000000000000000"}]}]},"staking":{"params":{"bond_denom":"uhby"}}}}`
+
+func TestRewriteGenesis(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "  initial_height: \"1\"\n  genesis_time: \"2023-01-01T00:00:00Z\"\n  app_state:\n    staking:\n      params:\n        max_validators: 50\n")
+	strExported := filepath.Join(root, "exported.json")
+	require.NoError(t, os.WriteFile(strExported, []byte(testExportedGenesis), 0o644))
+
+	opt := &types.GenesisOption{Option: newTestOption(strConfig), GenesisTime: "2024-01-01T00:00:00Z"}
+	m := NewGenesisMigrator(opt).(*GenesisMigrator)
+	ic, err := m.builder.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, m.builder.checkConfig(ic))
+
+	//exported initial height survives the config override, other overrides are applied
+	genesis, err := loadGenesisFile(strExported)
+	require.NoError(t, err)
+	strChainID, err := m.rewriteGenesis(genesis, ic)
+	require.NoError(t, err)
+	require.Equal(t, "test_9000-1", strChainID)
+	require.Equal(t, "test_9000-1", genesis["chain_id"])
+	require.Equal(t, "121", genesis["initial_height"])
+	require.Equal(t, "2024-01-01T00:00:00Z", genesis["genesis_time"])
+	staking := genesis["app_state"].(map[string]interface{})["staking"].(map[string]interface{})["params"].(map[string]interface{})
+	require.EqualValues(t, 50, staking["max_validators"])
+	require.Equal(t, "uhby", staking["bond_denom"])
+
+	//flags win
+	opt.NewChainID, opt.InitialHeight = "test_9001-1", "200"
+	genesis, err = loadGenesisFile(strExported)
+	require.NoError(t, err)
+	strChainID, err = m.rewriteGenesis(genesis, ic)
+	require.NoError(t, err)
+	require.Equal(t, "test_9001-1", strChainID)
+	require.Equal(t, "test_9001-1", genesis["chain_id"])
+	require.Equal(t, "200", genesis["initial_height"])
+
+	opt.GenesisTime = "yesterday"
+	_, err = m.rewriteGenesis(genesis, ic)
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package chain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigTemplate = `version: 1
+accounts:
+- name: validator1
+  coins: ["1000uhby"]
+- name: validator2
+  coins: ["1000uhby"]
+validators:
+- name: validator1
+  bonded: 100uhby
+  home: "%s"
+  ip: "127.0.0.1"
+  config:
+    consensus:
+      timeout_commit: "1s"
+    moniker: "node1"
+    rpc:
+      laddr: "tcp://0.0.0.0:26657"
+    p2p:
+      laddr: "tcp://0.0.0.0:26656"
+- name: validator2
+  bonded: 100uhby
+  home: "%s"
+  ip: "127.0.0.1"
+  config:
+    consensus:
+      timeout_commit: "1s"
+    moniker: "node2"
+    rpc:
+      laddr: "tcp://0.0.0.0:36657"
+    p2p:
+      laddr: "tcp://0.0.0.0:36656"
+genesis:
+  chain_id: "test_9000-1"
+`
+
+func newTestOption(strConfig string) types.Option {
+	return types.Option{
+		ConfigPath:     strConfig,
+		NodeCmd:        types.DEFAULT_NODE_CMD,
+		ChainID:        types.DEFAULT_CHAIN_ID,
+		KeyPhrase:      types.DEFAULT_KEY_PHRASE,
+		KeyringBackend: types.KEYRING_BACKEND_TEST,
+		AllowedRoots:   []string{filepath.Dir(strConfig)},
+	}
+}
+
+// writeTestConfig writes config file of testConfigTemplate with extra appended into a temp dir, the homes of
+// validator1 and validator2 are node1 and node2 of the dir. it returns the dir and path of config file.
+func writeTestConfig(t *testing.T, extra string) (root, strConfig string) {
+	root = t.TempDir()
+	strConfig = filepath.Join(root, "config.yml")
+	strContent := fmt.Sprintf(testConfigTemplate, filepath.Join(root, "node1"), filepath.Join(root, "node2")) + extra
+	require.NoError(t, os.WriteFile(strConfig, []byte(strContent), 0o644))
+	return root, strConfig
+}
@@ -0,0 +1,117 @@
+package chain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+)
+
+// checkHooks checks hooks of config file refer to build stages and have valid failure semantics
+func (m *ChainBuilder) checkHooks(ic *types.IgniteConfig, stages []*buildStage) error {
+	var names = make(map[string]bool)
+	for _, stage := range stages {
+		names[stage.Name] = true
+	}
+	for strStage, hooks := range ic.Hooks {
+		if !names[strStage] {
+			return log.Errorf("hooks of unknown stage %s", strStage)
+		}
+		for _, h := range append(append([]types.HookConfig{}, hooks.Before...), hooks.After...) {
+			if strings.TrimSpace(h.Run) == "" {
+				return log.Errorf("hook of stage %s has nothing to run", strStage)
+			}
+			switch h.OnFailure {
+			case "", types.HOOK_ON_FAILURE_ABORT, types.HOOK_ON_FAILURE_WARN, types.HOOK_ON_FAILURE_IGNORE:
+			default:
+				return log.Errorf("hook [%s] of stage %s on_failure %s must be %s, %s or %s", h.Run, strStage, h.OnFailure,
+					types.HOOK_ON_FAILURE_ABORT, types.HOOK_ON_FAILURE_WARN, types.HOOK_ON_FAILURE_IGNORE)
+			}
+		}
+	}
+	return nil
+}
+
+// runHooks runs hooks of stage before or after it in order. a failed hook aborts the stage unless its on_failure
+// is warn (a warning is logged) or ignore.
+func (m *ChainBuilder) runHooks(ic *types.IgniteConfig, strStage, strWhen string) (err error) {
+	hooks := ic.Hooks[strStage].Before
+	if strWhen == types.HOOK_AFTER {
+		hooks = ic.Hooks[strStage].After
+	}
+	for _, h := range hooks {
+		if !h.PerValidator {
+			err = m.runHook(ic, h, strStage, strWhen, nil)
+		} else {
+			for i := range ic.Validators {
+				if err = m.runHook(ic, h, strStage, strWhen, &ic.Validators[i]); err != nil {
+					break
+				}
+			}
+		}
+		if err == nil {
+			continue
+		}
+		switch h.OnFailure {
+		case types.HOOK_ON_FAILURE_IGNORE:
+		case types.HOOK_ON_FAILURE_WARN:
+			log.Warnf("%s", err)
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ChainBuilder) runHook(ic *types.IgniteConfig, h types.HookConfig, strStage, strWhen string, v *types.ValidatorConfig) error {
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	cmd.Env = m.hookEnv(ic, strStage, strWhen, v)
+	//scripts are relative to config file
+	cmd.Dir = filepath.Dir(m.option.ConfigPath)
+	strName := fmt.Sprintf("%s %s", strWhen, strStage)
+	if v != nil {
+		strName += fmt.Sprintf(" [%s]", v.Name)
+	}
+	output, err := cmd.Shell(h.Run)
+	if err != nil {
+		return fmt.Errorf("hook %s [%s] error [%s]", strName, h.Run, err)
+	}
+	if output != "" {
+		log.Infof("hook %s [%s] output\n%s", strName, h.Run, output)
+	}
+	log.Infof("hook %s [%s] ok", strName, h.Run)
+	return nil
+}
+
+// hookEnv returns environment variables describing stage and validator of hook
+func (m *ChainBuilder) hookEnv(ic *types.IgniteConfig, strStage, strWhen string, v *types.ValidatorConfig) []string {
+	strHome := m.strNode0Home
+	maker := m.maker
+	if v != nil {
+		maker = m.makerOf(v)
+	}
+	var homes []string
+	for _, iv := range ic.Validators {
+		homes = append(homes, iv.Home)
+	}
+	env := []string{
+		types.HOOK_ENV_STAGE + "=" + strStage,
+		types.HOOK_ENV_WHEN + "=" + strWhen,
+		types.HOOK_ENV_CHAIN_ID + "=" + m.option.ChainID,
+		types.HOOK_ENV_NODE_CMD + "=" + maker.NodeCmd(),
+		types.HOOK_ENV_CONFIG + "=" + m.option.ConfigPath,
+		types.HOOK_ENV_NODE0_HOME + "=" + m.strNode0Home,
+		types.HOOK_ENV_HOMES + "=" + strings.Join(homes, " "),
+	}
+	if v != nil {
+		strHome = v.Home
+		env = append(env, types.HOOK_ENV_VALIDATOR+"="+v.Name)
+	}
+	return append(env,
+		types.HOOK_ENV_HOME+"="+strHome,
+		types.HOOK_ENV_GENESIS+"="+utils.MakeCosmosConfigPath(strHome, types.FILE_NAME_GENESIS),
+	)
+}
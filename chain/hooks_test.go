@@ -0,0 +1,61 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testHooksConfig = `hooks:
+  merge_genesis:
+    before:
+    - run: echo "$COSMOS_CLI_HOOK $COSMOS_CLI_STAGE $COSMOS_CLI_HOME $COSMOS_CLI_NODE_CMD" >> hooks.log
+    after:
+    - run: ./patch.sh
+      per_validator: true
+    - run: exit 3
+      on_failure: warn
+  sync_genesis:
+    after:
+    - run: exit 1
+`
+
+func TestRunHooks(t *testing.T) {
+	root, strConfig := writeTestConfig(t, testHooksConfig)
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	strScript := "#!/bin/sh\necho \"$COSMOS_CLI_VALIDATOR $COSMOS_CLI_GENESIS $COSMOS_CLI_CHAIN_ID $COSMOS_CLI_NODE_CMD\" >> hooks.log\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "patch.sh"), []byte(strScript), 0o755))
+	strOverlay := filepath.Join(root, "binary.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator2\n  binary: hobbyd-v2\n"), 0o644))
+
+	opt := newTestOption(strConfig)
+	opt.ConfigOverlays = []string{strOverlay}
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, b.checkConfig(ic))
+	stages := []*buildStage{{Name: types.STAGE_MERGE_GENESIS}, {Name: types.STAGE_SYNC_GENESIS}}
+	require.NoError(t, b.checkHooks(ic, stages))
+	require.Error(t, b.checkHooks(ic, stages[:1]))
+
+	require.NoError(t, b.runHooks(ic, types.STAGE_MERGE_GENESIS, types.HOOK_BEFORE))
+	require.NoError(t, b.runHooks(ic, types.STAGE_MERGE_GENESIS, types.HOOK_AFTER))
+	require.Error(t, b.runHooks(ic, types.STAGE_SYNC_GENESIS, types.HOOK_AFTER))
+	require.NoError(t, b.runHooks(ic, types.STAGE_SYNC_GENESIS, types.HOOK_BEFORE))
+
+	data, err := os.ReadFile(filepath.Join(root, "hooks.log"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"before merge_genesis " + home1 + " hobbyd",
+		"validator1 " + filepath.Join(home1, "config", "genesis.json") + " test_9000-1 hobbyd",
+		//per validator hooks see the binary of validator
+		"validator2 " + filepath.Join(home2, "config", "genesis.json") + " test_9000-1 hobbyd-v2",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+
+	ic.Hooks[types.STAGE_SYNC_GENESIS] = types.StageHooks{After: []types.HookConfig{{Run: "true", OnFailure: "retry"}}}
+	require.Error(t, b.checkHooks(ic, stages))
+}
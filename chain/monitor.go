@@ -0,0 +1,211 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/rpc"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+)
+
+// NetworkMonitor polls RPC endpoints of every validator in config file and prints height, catching up, peers,
+// voting power and missed blocks per node, flagging unreachable and stalled nodes and forks.
+type NetworkMonitor struct {
+	option         *types.MonitorOption //monitor option
+	builder        *ChainBuilder        //chain builder of network
+	nodes          []*nodeHealth        //health of validator nodes in config order
+	nCheckedHeight int64                //last height of commits checked for missed blocks
+	out            io.Writer
+	now            func() time.Time
+}
+
+// nodeHealth is the health of a validator node collected by polling its RPC endpoint
+type nodeHealth struct {
+	Name        string
+	RPC         string
+	Address     string //consensus address of validator key in home, or of node status if home is not local
+	Height      int64
+	AppHash     string //app hash of commit at the lowest height of reachable nodes
+	CatchingUp  bool
+	Peers       int
+	VotingPower int64
+	Missed      int    //missed blocks since monitor started, including up to MONITOR_MISSED_LOOKBACK blocks before it
+	Status      string //ok, unreachable, stalled or forked of last round
+	Err         error  //RPC error of last round
+	client      *rpc.Client
+	lastChanged time.Time //time of last height change
+}
+
+func NewNetworkMonitor(opt *types.MonitorOption) api.ManagerApi {
+	if opt == nil {
+		panic("monitor option is nil")
+	}
+	return &NetworkMonitor{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+		out:     os.Stdout,
+		now:     time.Now,
+	}
+}
+
+func (m *NetworkMonitor) Run() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = types.MONITOR_DEFAULT_INTERVAL * time.Second
+	}
+	if opt.Stall <= 0 {
+		opt.Stall = types.MONITOR_DEFAULT_STALL * time.Second
+	}
+	m.nodes = nil
+	for _, v := range ic.Validators {
+		strRPC := ic.GetValidatorRPC(v.Name)
+		n := &nodeHealth{Name: v.Name, RPC: strRPC, client: rpc.NewClient(strRPC)}
+		//a node unreachable from the first round still has its missed blocks counted
+		strKeyPath := utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_PRIV_VALIDATOR_KEY)
+		if n.Address, err = utils.LoadValidatorAddress(strKeyPath); err != nil {
+			log.Debugf("validator [%s] address resolved by node status [%s]", v.Name, err)
+			err = nil
+		}
+		m.nodes = append(m.nodes, n)
+	}
+	for n := 0; opt.Count == 0 || n < opt.Count; n++ {
+		if n > 0 {
+			time.Sleep(opt.Interval)
+		}
+		issues := m.poll(context.Background())
+		if err = m.print(issues); err != nil {
+			return err
+		}
+		if len(issues) != 0 && opt.ExitCode {
+			return log.Errorf("network monitor found %d issue(s)", len(issues))
+		}
+	}
+	return nil
+}
+
+// poll queries every node once and returns issues found in this round
+func (m *NetworkMonitor) poll(ctx context.Context) (issues []string) {
+	now := m.now()
+	var reachable []*nodeHealth
+	for _, n := range m.nodes {
+		var status *rpc.StatusResult
+		var netInfo *rpc.NetInfoResult
+		n.Status = types.NODE_STATUS_OK
+		if status, n.Err = n.client.Status(ctx); n.Err == nil {
+			netInfo, n.Err = n.client.NetInfo(ctx)
+		}
+		if n.Err != nil {
+			n.Status = types.NODE_STATUS_UNREACHABLE
+			issues = append(issues, fmt.Sprintf("validator [%s] %s unreachable [%s]", n.Name, n.RPC, n.Err))
+			continue
+		}
+		nHeight := status.SyncInfo.LatestBlockHeight
+		if nHeight != n.Height || n.lastChanged.IsZero() {
+			n.Height, n.lastChanged = nHeight, now
+		} else if d := now.Sub(n.lastChanged); d >= m.option.Stall {
+			n.Status = types.NODE_STATUS_STALLED
+			issues = append(issues, fmt.Sprintf("validator [%s] stalled at height %d for %s", n.Name, n.Height, d.Round(time.Second)))
+		}
+		if n.Address == "" {
+			n.Address = status.ValidatorInfo.Address
+		}
+		n.CatchingUp, n.Peers = status.SyncInfo.CatchingUp, netInfo.NPeers
+		reachable = append(reachable, n)
+	}
+	if len(reachable) == 0 {
+		return issues
+	}
+	ref := reachable[0]
+	powers := make(map[string]int64)
+	if vals, err := ref.client.Validators(ctx, 0); err != nil {
+		issues = append(issues, fmt.Sprintf("validator [%s] query validators error [%s]", ref.Name, err))
+	} else {
+		for _, v := range vals.Validators {
+			powers[v.Address] = v.VotingPower
+		}
+	}
+	for _, n := range m.nodes {
+		n.VotingPower = powers[n.Address]
+	}
+	//compare app hashes at the lowest height of reachable nodes
+	nMinHeight := ref.Height
+	for _, n := range reachable {
+		if n.Height < nMinHeight {
+			nMinHeight = n.Height
+		}
+	}
+	if nMinHeight <= 0 {
+		return issues
+	}
+	for _, n := range reachable {
+		commit, err := n.client.Commit(ctx, nMinHeight)
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("validator [%s] query commit at height %d error [%s]", n.Name, nMinHeight, err))
+			continue
+		}
+		n.AppHash = commit.SignedHeader.Header.AppHash
+		if n != ref && n.AppHash != ref.AppHash {
+			n.Status = types.NODE_STATUS_FORKED
+			issues = append(issues, fmt.Sprintf("validator [%s] app hash %s at height %d differs from [%s] %s, network forked",
+				n.Name, n.AppHash, nMinHeight, ref.Name, ref.AppHash))
+		}
+	}
+	issues = append(issues, m.countMissed(ctx, ref, powers, nMinHeight)...)
+	return issues
+}
+
+// countMissed counts blocks not signed by active validator nodes (address in validator set) from last checked height
+// to height by commits of reference node, the first round looks back MONITOR_MISSED_LOOKBACK blocks at most
+func (m *NetworkMonitor) countMissed(ctx context.Context, ref *nodeHealth, powers map[string]int64, nHeight int64) (issues []string) {
+	nFrom := m.nCheckedHeight + 1
+	if nLookback := nHeight - types.MONITOR_MISSED_LOOKBACK + 1; nFrom < nLookback {
+		nFrom = nLookback
+	}
+	if nFrom < 1 {
+		nFrom = 1
+	}
+	for h := nFrom; h <= nHeight; h++ {
+		commit, err := ref.client.Commit(ctx, h)
+		if err != nil {
+			return append(issues, fmt.Sprintf("validator [%s] query commit at height %d error [%s]", ref.Name, h, err))
+		}
+		for _, n := range m.nodes {
+			if _, ok := powers[n.Address]; ok && !commit.Signed(n.Address) {
+				n.Missed++
+			}
+		}
+		m.nCheckedHeight = h
+	}
+	return issues
+}
+
+func (m *NetworkMonitor) print(issues []string) error {
+	w := tabwriter.NewWriter(m.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "[%s]\n", m.now().Format(types.TIME_FORMAT_DISPLAY))
+	fmt.Fprintln(w, "NAME\tRPC\tHEIGHT\tCATCHING UP\tPEERS\tVOTING POWER\tMISSED\tSTATUS")
+	for _, n := range m.nodes {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%v\t%d\t%d\t%d\t%s\n", n.Name, n.RPC, n.Height, n.CatchingUp, n.Peers, n.VotingPower, n.Missed, n.Status)
+	}
+	for _, s := range issues {
+		fmt.Fprintf(w, "  - %s\n", s)
+	}
+	return w.Flush()
+}
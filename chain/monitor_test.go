@@ -0,0 +1,183 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/civet148/cosmos-cli/rpc"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRPC is a fake CometBFT RPC endpoint of a validator node
+type fakeRPC struct {
+	sync.Mutex
+	address    string
+	height     int64
+	catchingUp bool
+	appHash    string
+	peers      int
+	powers     map[string]int64 //validator set
+	missed     map[int64]string //validator address not signing block by height, shared by network
+	signers    []string         //all validator addresses signing commits
+	delay      time.Duration    //delay of responses, eg. a hanging node
+	down       bool             //node is unreachable
+}
+
+func (f *fakeRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	delay, down := f.delay, f.down
+	f.Unlock()
+	if down {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	select {
+	case <-time.After(delay):
+	case <-r.Context().Done():
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	var strResult string
+	switch r.URL.Path {
+	case "/status":
+		strResult = fmt.Sprintf(`{"node_info":{"id":"id-%s"},"sync_info":{"latest_block_height":"%d","latest_app_hash":"%s","catching_up":%v},"validator_info":{"address":"%s","voting_power":"0"}}`,
+			f.address, f.height, f.appHash, f.catchingUp, f.address)
+	case "/net_info":
+		strResult = fmt.Sprintf(`{"listening":true,"n_peers":"%d","peers":[]}`, f.peers)
+	case "/validators":
+		var vals []string
+		for strAddr, nPower := range f.powers {
+			vals = append(vals, fmt.Sprintf(`{"address":"%s","voting_power":"%d"}`, strAddr, nPower))
+		}
+		strResult = fmt.Sprintf(`{"block_height":"%d","validators":[%s],"count":"%d","total":"%d"}`, f.height, strings.Join(vals, ","), len(vals), len(vals))
+	case "/commit":
+		nHeight, _ := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+		if nHeight > f.height {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"height %d must be less than or equal to the current blockchain height %d"}}`, nHeight, f.height)
+			return
+		}
+		var sigs []string
+		for _, strAddr := range f.signers {
+			if f.missed[nHeight] == strAddr {
+				sigs = append(sigs, `{"block_id_flag":1,"validator_address":""}`)
+				continue
+			}
+			sigs = append(sigs, fmt.Sprintf(`{"block_id_flag":2,"validator_address":"%s"}`, strAddr))
+		}
+		strResult = fmt.Sprintf(`{"signed_header":{"header":{"height":"%d","app_hash":"%s"},"commit":{"block_id":{"hash":"B%d"},"signatures":[%s]}},"canonical":true}`,
+			nHeight, f.appHash, nHeight, strings.Join(sigs, ","))
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":%s}`, strResult)
+}
+
+func (f *fakeRPC) set(fn func(f *fakeRPC)) {
+	f.Lock()
+	defer f.Unlock()
+	fn(f)
+}
+
+// newFakeNetwork starts fake RPC endpoints of the two validators of test config and writes config file with
+// RPC listen ports of fake endpoints
+func newFakeNetwork(t *testing.T) (string, []*fakeRPC) {
+	_, strConfig := writeTestConfig(t, "")
+	data, err := os.ReadFile(strConfig)
+	require.NoError(t, err)
+	strContent := string(data)
+	powers := map[string]int64{"ADDR1": 10, "ADDR2": 10}
+	missed := map[int64]string{}
+	var fakes []*fakeRPC
+	for i, strAddr := range []string{"ADDR1", "ADDR2"} {
+		f := &fakeRPC{address: strAddr, height: 5, appHash: "AA", peers: 1, powers: powers, missed: missed, signers: []string{"ADDR1", "ADDR2"}}
+		ts := httptest.NewServer(f)
+		t.Cleanup(ts.Close)
+		u, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+		strContent = strings.Replace(strContent, []string{"tcp://0.0.0.0:26657", "tcp://0.0.0.0:36657"}[i], "tcp://0.0.0.0:"+u.Port(), 1)
+		fakes = append(fakes, f)
+	}
+	require.NoError(t, os.WriteFile(strConfig, []byte(strContent), 0o644))
+	return strConfig, fakes
+}
+
+func TestNetworkMonitor(t *testing.T) {
+	strConfig, fakes := newFakeNetwork(t)
+	fakes[0].set(func(f *fakeRPC) { f.missed[4], f.missed[5] = "ADDR2", "ADDR2" })
+
+	opt := &types.MonitorOption{Option: newTestOption(strConfig), Count: 2, Interval: time.Millisecond, ExitCode: true}
+	m := NewNetworkMonitor(opt).(*NetworkMonitor)
+	m.out = io.Discard
+	require.NoError(t, m.Run())
+	require.Equal(t, int64(5), m.nodes[0].Height)
+	require.Equal(t, int64(10), m.nodes[1].VotingPower)
+	require.Equal(t, 1, m.nodes[1].Peers)
+	require.Equal(t, 0, m.nodes[0].Missed)
+	require.Equal(t, 2, m.nodes[1].Missed)
+	require.Equal(t, types.NODE_STATUS_OK, m.nodes[1].Status)
+
+	//stalled node
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	fakes[0].set(func(f *fakeRPC) { f.height = 6 })
+	issues := m.poll(context.Background())
+	require.Len(t, issues, 0)
+	now = now.Add(opt.Stall + time.Second)
+	fakes[1].set(func(f *fakeRPC) { f.height = 6 })
+	issues = m.poll(context.Background())
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "validator [validator1] stalled at height 6")
+	require.Equal(t, types.NODE_STATUS_STALLED, m.nodes[0].Status)
+
+	//forked node makes monitor exit non-zero
+	fakes[1].set(func(f *fakeRPC) { f.height, f.appHash = 7, "BB" })
+	fakes[0].set(func(f *fakeRPC) { f.height = 7 })
+	issues = m.poll(context.Background())
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "validator [validator2] app hash BB at height 7 differs from [validator1] AA")
+	require.Error(t, m.Run())
+
+	m.nodes[1].client = rpc.NewClient("tcp://127.0.0.1:1")
+	issues = m.poll(context.Background())
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "validator [validator2] tcp://127.0.0.1:")
+	require.Equal(t, types.NODE_STATUS_UNREACHABLE, m.nodes[1].Status)
+	opt.ExitCode = false
+	require.NoError(t, m.Run())
+}
+
+func TestNetworkMonitorUnreachableMissed(t *testing.T) {
+	strConfig, fakes := newFakeNetwork(t)
+	fakes[0].set(func(f *fakeRPC) { f.missed[4], f.missed[5] = "ADDR2", "ADDR2" })
+	fakes[1].set(func(f *fakeRPC) { f.down = true })
+	//address of an unreachable node is resolved by its validator key
+	strKeyPath := filepath.Join(filepath.Dir(strConfig), "node2", "config", types.FILE_NAME_PRIV_VALIDATOR_KEY)
+	require.NoError(t, os.MkdirAll(filepath.Dir(strKeyPath), 0o755))
+	require.NoError(t, os.WriteFile(strKeyPath, []byte(`{"address":"ADDR2"}`), 0o600))
+
+	opt := &types.MonitorOption{Option: newTestOption(strConfig), Count: 1}
+	m := NewNetworkMonitor(opt).(*NetworkMonitor)
+	m.out = io.Discard
+	require.NoError(t, m.Run())
+	require.Equal(t, types.NODE_STATUS_UNREACHABLE, m.nodes[1].Status)
+	require.Equal(t, "ADDR2", m.nodes[1].Address)
+	require.Equal(t, int64(10), m.nodes[1].VotingPower)
+	require.Equal(t, 2, m.nodes[1].Missed)
+	//address of node without local key is resolved by its status
+	require.Equal(t, "ADDR1", m.nodes[0].Address)
+	require.Equal(t, 0, m.nodes[0].Missed)
+}
@@ -0,0 +1,550 @@
+package chain
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/civet148/cosmos-cli/config"
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"gopkg.in/yaml.v2"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// SnapshotManager archives every validator home of a built network together with config file and build report
+// into a compressed and checksummed bundle, and restores bundles to the same or remapped home paths.
+type SnapshotManager struct {
+	option  *types.SnapshotOption //snapshot option
+	builder *ChainBuilder         //chain builder of network
+}
+
+func NewSnapshotManager(opt *types.SnapshotOption) *SnapshotManager {
+	if opt == nil {
+		panic("snapshot option is nil")
+	}
+	m := &SnapshotManager{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+	if opt.Dir == "" {
+		opt.Dir = types.DEFAULT_SNAPSHOT_DIR
+		if m.builder.network != nil {
+			opt.Dir = m.builder.network.SnapshotsDir()
+		}
+	}
+	return m
+}
+
+// Create archives the network into snapshot bundle
+func (m *SnapshotManager) Create() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	now := time.Now()
+	if opt.Name == "" {
+		opt.Name = fmt.Sprintf("%s-%s", ic.Genesis.ChainID, now.Format(types.TIME_FORMAT_FILE_NAME))
+	}
+	manifest := &types.SnapshotManifest{
+		Name:    opt.Name,
+		ChainID: ic.Genesis.ChainID,
+		Time:    now,
+		Files:   make(map[string]string),
+	}
+	//entry name => file content, config file is archived rendered and merged with overlays so that
+	//the snapshot restores the network as it was built and every validator home can be remapped
+	var files = make(map[string][]byte)
+	if files[types.DEFAULT_CONFIG_FILE], err = config.Load(append([]string{opt.ConfigPath}, opt.ConfigOverlays...)...); err != nil {
+		return log.Errorf("load config file %s error [%s]", opt.ConfigPath, err)
+	}
+	if opt.ReportPath != "" {
+		var data []byte
+		if data, err = os.ReadFile(opt.ReportPath); err == nil {
+			files[types.FILE_NAME_BUILD_REPORT] = data
+		}
+	}
+	for _, v := range ic.Validators {
+		if !utils.HasChainData(v.Home) {
+			return log.Errorf("validator [%s] home %s has no chain data", v.Name, v.Home)
+		}
+		manifest.Validators = append(manifest.Validators, &types.SnapshotValidator{Name: v.Name, Home: v.Home})
+	}
+	strBundle := m.bundlePath(opt.Name)
+	if _, err = os.Stat(strBundle); err == nil {
+		return log.Errorf("snapshot %s already exists", strBundle)
+	}
+	if err = m.writeBundle(strBundle, manifest, files); err != nil {
+		_ = os.Remove(strBundle)
+		return log.Errorf("write snapshot %s error [%s]", strBundle, err)
+	}
+	var strSum string
+	if strSum, err = utils.FileSHA256(strBundle); err != nil {
+		return err
+	}
+	strLine := fmt.Sprintf("%s  %s\n", strSum, filepath.Base(strBundle))
+	if err = os.WriteFile(strBundle+types.SNAPSHOT_CHECKSUM_EXT, []byte(strLine), 0o644); err != nil {
+		return err
+	}
+	b.journal(types.JOURNAL_CMD_SNAPSHOT_CREATE, opt.Name, nil)
+	fmt.Printf("snapshot %s created at %s (sha256 %s)\n", opt.Name, strBundle, strSum)
+	return nil
+}
+
+// Restore verifies snapshot bundle and restores validator homes and config file from it
+func (m *SnapshotManager) Restore() (err error) {
+	b := m.builder
+	opt := m.option
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	strBundle := m.bundlePath(opt.Name)
+	if err = verifyBundleChecksum(strBundle); err != nil {
+		return log.Errorf(err.Error())
+	}
+	strStaging := filepath.Join(opt.Dir, types.SNAPSHOT_STAGING_PREFIX+opt.Name)
+	if err = os.RemoveAll(strStaging); err != nil {
+		return err
+	}
+	defer os.RemoveAll(strStaging)
+	if err = utils.UntarGz(strBundle, strStaging, nil); err != nil {
+		return log.Errorf("extract snapshot %s error [%s]", strBundle, err)
+	}
+	var manifest *types.SnapshotManifest
+	if manifest, err = readManifestFile(filepath.Join(strStaging, types.SNAPSHOT_MANIFEST)); err != nil {
+		return log.Errorf(err.Error())
+	}
+	if err = readChecksumsFile(filepath.Join(strStaging, types.SNAPSHOT_CHECKSUMS), manifest); err != nil {
+		return log.Errorf(err.Error())
+	}
+	if err = verifyStaging(strStaging, manifest); err != nil {
+		return log.Errorf("snapshot %s corrupted: %s", strBundle, err)
+	}
+	homes := m.makeRestoreHomes(manifest)
+	if err = m.prepareRestoreHomes(homes); err != nil {
+		return err
+	}
+	for _, v := range manifest.Validators {
+		strHome := homes[v.Name]
+		if err = moveDir(filepath.Join(strStaging, snapshotHomeEntry(v.Name)), strHome, opt.Debug); err != nil {
+			return log.Errorf("restore validator [%s] home %s error [%s]", v.Name, strHome, err)
+		}
+		if strHome != v.Home {
+			if err = rewriteHomePaths(strHome, v.Home); err != nil {
+				return log.Errorf("rewrite validator [%s] home paths error [%s]", v.Name, err)
+			}
+		}
+		log.Infof("validator [%s] home restored to %s", v.Name, strHome)
+	}
+	if err = m.restoreConfig(strStaging, manifest, homes); err != nil {
+		return err
+	}
+	b.journal(types.JOURNAL_CMD_SNAPSHOT_RESTORE, opt.Name, nil)
+	fmt.Printf("snapshot %s restored\n", opt.Name)
+	return nil
+}
+
+// List prints snapshots in snapshots directory
+func (m *SnapshotManager) List() (err error) {
+	var matches []string
+	if matches, err = filepath.Glob(filepath.Join(m.option.Dir, "*"+types.SNAPSHOT_FILE_EXT)); err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHAIN ID\tVALIDATORS\tTIME\tSIZE")
+	for _, strPath := range matches {
+		manifest, err := readBundleManifest(strPath)
+		if err != nil {
+			log.Warnf("read snapshot %s error [%s]", strPath, err)
+			continue
+		}
+		var nSize int64
+		if fi, err := os.Stat(strPath); err == nil {
+			nSize = fi.Size()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", manifest.Name, manifest.ChainID, len(manifest.Validators),
+			manifest.Time.Format(types.TIME_FORMAT_DISPLAY), utils.FormatBytes(nSize))
+	}
+	return w.Flush()
+}
+
+func (m *SnapshotManager) bundlePath(strName string) string {
+	return filepath.Join(m.option.Dir, strName+types.SNAPSHOT_FILE_EXT)
+}
+
+// writeBundle writes manifest as the first entry so that it can be listed without extracting the whole bundle.
+// checksums of entries are computed from the bytes being archived and written as the last entry.
+func (m *SnapshotManager) writeBundle(strBundle string, manifest *types.SnapshotManifest, files map[string][]byte) (err error) {
+	if err = os.MkdirAll(filepath.Dir(strBundle), 0o755); err != nil {
+		return err
+	}
+	var file *os.File
+	if file, err = os.Create(strBundle); err != nil {
+		return err
+	}
+	defer file.Close()
+	zw := gzip.NewWriter(file)
+	tw := tar.NewWriter(zw)
+	var data []byte
+	if data, err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return err
+	}
+	if err = writeTarFile(tw, types.SNAPSHOT_MANIFEST, data); err != nil {
+		return err
+	}
+	for strName, data := range files {
+		if err = writeTarFile(tw, strName, data); err != nil {
+			return err
+		}
+		manifest.Files[strName] = utils.BytesSHA256(data)
+	}
+	for _, v := range manifest.Validators {
+		if err = utils.AddDirToTarWithSums(tw, v.Home, snapshotHomeEntry(v.Name), manifest.Files); err != nil {
+			return fmt.Errorf("archive validator [%s] home %s error [%s]", v.Name, v.Home, err)
+		}
+	}
+	if data, err = json.MarshalIndent(manifest.Files, "", "  "); err != nil {
+		return err
+	}
+	if err = writeTarFile(tw, types.SNAPSHOT_CHECKSUMS, data); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// makeRestoreHomes decides restore path of every validator home: explicit mapping first, then restore root,
+// then home root of named network and the original path at last.
+func (m *SnapshotManager) makeRestoreHomes(manifest *types.SnapshotManifest) map[string]string {
+	opt := m.option
+	homes := make(map[string]string)
+	for _, v := range manifest.Validators {
+		strHome := v.Home
+		if p, ok := opt.HomeMap[v.Name]; ok {
+			strHome = p
+		} else if opt.RestoreRoot != "" {
+			strHome = filepath.Join(opt.RestoreRoot, v.Name)
+		} else if opt.HomeRoot != "" {
+			strHome = filepath.Join(opt.HomeRoot, v.Name)
+		}
+		homes[v.Name] = filepath.Clean(strHome)
+	}
+	return homes
+}
+
+func (m *SnapshotManager) prepareRestoreHomes(homes map[string]string) (err error) {
+	opt := m.option
+	allowedRoots := m.builder.allowedRoots()
+	if opt.RestoreRoot != "" {
+		allowedRoots = append(allowedRoots, opt.RestoreRoot)
+	}
+	var existHomes []string
+	for strName, strHome := range homes {
+		if err = utils.CheckRemovableHome(strHome, allowedRoots); err != nil {
+			return log.Errorf("validator [%s] %s", strName, err)
+		}
+		if _, err = os.Stat(strHome); err == nil {
+			if !utils.HasChainData(strHome) && !utils.IsEmptyDir(strHome) && !opt.Force {
+				return log.Errorf("validator [%s] home %s is not empty and contains no chain data, use --force to overwrite it", strName, strHome)
+			}
+			existHomes = append(existHomes, strHome)
+		}
+	}
+	sort.Strings(existHomes)
+	if len(existHomes) != 0 && !opt.Force {
+		if !utils.Confirm(fmt.Sprintf("validator homes %v already exist, overwrite them?", existHomes)) {
+			return log.Errorf("validator homes %v already exist, use --force to overwrite them", existHomes)
+		}
+	}
+	for _, strHome := range existHomes {
+		if opt.Backup {
+			if err = m.builder.backupHome(strHome); err != nil {
+				return err
+			}
+		}
+		if err = os.RemoveAll(strHome); err != nil {
+			return log.Errorf(err.Error())
+		}
+	}
+	return nil
+}
+
+// restoreConfig writes config file of snapshot with remapped validator homes to config path, the existing
+// config file is kept as .bak if it differs. files are replaced atomically, build report is restored to report
+// path if any.
+func (m *SnapshotManager) restoreConfig(strStaging string, manifest *types.SnapshotManifest, homes map[string]string) (err error) {
+	opt := m.option
+	var data, old []byte
+	if data, err = os.ReadFile(filepath.Join(strStaging, types.DEFAULT_CONFIG_FILE)); err != nil {
+		return err
+	}
+	var changed = make(map[string]string)
+	for _, v := range manifest.Validators {
+		if homes[v.Name] != v.Home {
+			changed[v.Name] = homes[v.Name]
+		}
+	}
+	if len(changed) != 0 {
+		if data, err = rewriteConfigHomes(data, changed); err != nil {
+			return log.Errorf("rewrite config homes error [%s]", err)
+		}
+	}
+	c := confile.New(confile.DefaultYAMLEncodingCreator, opt.ConfigPath)
+	if old, err = os.ReadFile(opt.ConfigPath); err == nil && !bytes.Equal(old, data) {
+		c.WithBackup()
+	}
+	if err = c.WriteFile(data); err != nil {
+		return err
+	}
+	log.Infof("config restored to %s", opt.ConfigPath)
+	if opt.ReportPath != "" {
+		if data, err = os.ReadFile(filepath.Join(strStaging, types.FILE_NAME_BUILD_REPORT)); err == nil {
+			if err = confile.New(confile.DefaultJSONEncodingCreator, opt.ReportPath).WriteFile(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func snapshotHomeEntry(strName string) string {
+	return filepath.ToSlash(filepath.Join(types.SNAPSHOT_HOMES_SUBPATH, strName))
+}
+
+func writeTarFile(tw *tar.Writer, strName string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    strName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// hashDir puts checksum of every regular file in directory into files by entry name with prefix
+func hashDir(strDir, strPrefix string, files map[string]string) error {
+	return filepath.Walk(strDir, func(strPath string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.Mode().IsRegular() {
+			return err
+		}
+		strRel, err := filepath.Rel(strDir, strPath)
+		if err != nil {
+			return err
+		}
+		strSum, err := utils.FileSHA256(strPath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(filepath.Join(strPrefix, strRel))] = strSum
+		return nil
+	})
+}
+
+func verifyBundleChecksum(strBundle string) error {
+	data, err := os.ReadFile(strBundle + types.SNAPSHOT_CHECKSUM_EXT)
+	if err != nil {
+		return fmt.Errorf("read snapshot checksum error [%s]", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return fmt.Errorf("snapshot checksum file of %s is empty", strBundle)
+	}
+	strSum, err := utils.FileSHA256(strBundle)
+	if err != nil {
+		return err
+	}
+	if strSum != fields[0] {
+		return fmt.Errorf("snapshot %s checksum mismatch, expect %s got %s", strBundle, fields[0], strSum)
+	}
+	return nil
+}
+
+// verifyStaging checks every file listed in manifest exists in staging directory with expected checksum
+func verifyStaging(strStaging string, manifest *types.SnapshotManifest) error {
+	files := make(map[string]string)
+	for _, v := range manifest.Validators {
+		if err := hashDir(filepath.Join(strStaging, snapshotHomeEntry(v.Name)), snapshotHomeEntry(v.Name), files); err != nil {
+			return err
+		}
+	}
+	for strName, strSum := range manifest.Files {
+		if !strings.HasPrefix(strName, types.SNAPSHOT_HOMES_SUBPATH+"/") {
+			var err error
+			if files[strName], err = utils.FileSHA256(filepath.Join(strStaging, filepath.FromSlash(strName))); err != nil {
+				return err
+			}
+		}
+		if files[strName] != strSum {
+			return fmt.Errorf("file %s checksum mismatch", strName)
+		}
+	}
+	if len(files) != len(manifest.Files) {
+		return fmt.Errorf("files count %d mismatch with manifest %d", len(files), len(manifest.Files))
+	}
+	return nil
+}
+
+func readManifestFile(strPath string) (*types.SnapshotManifest, error) {
+	data, err := os.ReadFile(strPath)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot manifest error [%s]", err)
+	}
+	var manifest types.SnapshotManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot manifest error [%s]", err)
+	}
+	return &manifest, nil
+}
+
+// readChecksumsFile reads checksums of bundle entries into manifest, bundles without checksums entry carry them
+// in manifest
+func readChecksumsFile(strPath string, manifest *types.SnapshotManifest) error {
+	data, err := os.ReadFile(strPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot checksums error [%s]", err)
+	}
+	if err = json.Unmarshal(data, &manifest.Files); err != nil {
+		return fmt.Errorf("unmarshal snapshot checksums error [%s]", err)
+	}
+	return nil
+}
+
+// readBundleManifest reads manifest from the first entry of bundle
+func readBundleManifest(strBundle string) (*types.SnapshotManifest, error) {
+	file, err := os.Open(strBundle)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Name != types.SNAPSHOT_MANIFEST {
+		return nil, fmt.Errorf("manifest not found")
+	}
+	var manifest types.SnapshotManifest
+	if err = json.NewDecoder(io.LimitReader(tr, hdr.Size)).Decode(&manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// moveDir renames directory or copies it if source and destination are on different devices
+func moveDir(strSrc, strDst string, debug bool) (err error) {
+	if err = os.MkdirAll(filepath.Dir(strDst), 0o755); err != nil {
+		return err
+	}
+	if err = os.Rename(strSrc, strDst); err == nil {
+		return nil
+	}
+	cmd := utils.NewCmdExecutor(debug)
+	if _, err = cmd.Shell(fmt.Sprintf("%s -a %s %s", types.EXEC_CMD_COPY, strSrc, strDst)); err != nil {
+		return err
+	}
+	return os.RemoveAll(strSrc)
+}
+
+// rewriteHomePaths replaces absolute paths of old home in node config files with new home
+func rewriteHomePaths(strHome, strOldHome string) error {
+	for _, strName := range []string{types.FILE_NAME_APP, types.FILE_NAME_CONFIG, types.FILE_NAME_CLIENT} {
+		strPath := utils.MakeCosmosConfigPath(strHome, strName)
+		fi, err := os.Stat(strPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		data, err := os.ReadFile(strPath)
+		if err != nil {
+			return err
+		}
+		replaced := bytes.ReplaceAll(data, []byte(strOldHome), []byte(strHome))
+		if bytes.Equal(replaced, data) {
+			continue
+		}
+		if err = os.WriteFile(strPath, replaced, fi.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteConfigHomes replaces validator homes of config file keeping keys order
+func rewriteConfigHomes(data []byte, homes map[string]string) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for _, item := range doc {
+		if item.Key != "validators" {
+			continue
+		}
+		vals, _ := item.Value.([]interface{})
+		for j, val := range vals {
+			vm, ok := val.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			var strName string
+			for _, kv := range vm {
+				if kv.Key == "name" {
+					strName, _ = kv.Value.(string)
+				}
+			}
+			strHome, ok := homes[strName]
+			if !ok {
+				continue
+			}
+			var found bool
+			for i := range vm {
+				if vm[i].Key == "home" {
+					vm[i].Value = strHome
+					found = true
+				}
+			}
+			if !found {
+				vm = append(vm, yaml.MapItem{Key: "home", Value: strHome})
+			}
+			vals[j] = vm
+		}
+	}
+	return yaml.Marshal(doc)
+}
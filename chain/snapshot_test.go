@@ -0,0 +1,133 @@
+package chain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func writeTestHome(t *testing.T, strHome string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(strHome, "config"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(strHome, "data"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(strHome, "config", "genesis.json"), []byte(`{"chain_id":"test_9000-1"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(strHome, "config", "config.toml"), []byte(fmt.Sprintf("db_dir = \"%s/data\"\n", strHome)), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(strHome, "data", "priv_validator_state.json"), []byte(`{"height":"42"}`), 0o600))
+}
+
+func TestSnapshotCreateRestore(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	writeTestHome(t, home1)
+	writeTestHome(t, home2)
+	require.NoError(t, os.Chmod(filepath.Join(home1, "config", "config.toml"), 0o600))
+
+	strDir := filepath.Join(root, "snapshots")
+	opt := &types.SnapshotOption{Option: newTestOption(strConfig), Name: "snap1", Dir: strDir}
+	require.NoError(t, NewSnapshotManager(opt).Create())
+	require.FileExists(t, filepath.Join(strDir, "snap1.tar.gz.sha256"))
+
+	//existing homes are archived before being overwritten
+	opt = &types.SnapshotOption{Option: newTestOption(strConfig), Name: "snap1", Dir: strDir}
+	opt.Force, opt.Backup, opt.BackupDir = true, true, filepath.Join(root, "backups")
+	require.NoError(t, NewSnapshotManager(opt).Restore())
+	backups, err := filepath.Glob(filepath.Join(root, "backups", "*.tar.gz"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.True(t, utils.HasChainData(home1))
+
+	manifest, err := readBundleManifest(filepath.Join(strDir, "snap1.tar.gz"))
+	require.NoError(t, err)
+	require.Equal(t, "test_9000-1", manifest.ChainID)
+	require.Len(t, manifest.Validators, 2)
+
+	restoreRoot := filepath.Join(root, "restored")
+	opt = &types.SnapshotOption{
+		Option:      newTestOption(strConfig),
+		Name:        "snap1",
+		Dir:         strDir,
+		RestoreRoot: restoreRoot,
+		HomeMap:     map[string]string{"validator2": filepath.Join(root, "mapped", "node2")},
+	}
+	require.NoError(t, NewSnapshotManager(opt).Restore())
+
+	newHome1 := filepath.Join(restoreRoot, "validator1")
+	data, err := os.ReadFile(filepath.Join(newHome1, "data", "priv_validator_state.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{"height":"42"}`, string(data))
+	data, err = os.ReadFile(filepath.Join(newHome1, "config", "config.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), newHome1+"/data")
+	fi, err := os.Stat(filepath.Join(newHome1, "config", "config.toml"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	require.DirExists(t, filepath.Join(root, "mapped", "node2", "config"))
+
+	var ic types.IgniteConfig
+	data, err = os.ReadFile(strConfig)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &ic))
+	require.Equal(t, newHome1, ic.Validators[0].Home)
+	require.Equal(t, filepath.Join(root, "mapped", "node2"), ic.Validators[1].Home)
+	require.FileExists(t, strConfig+".bak")
+}
+
+func TestSnapshotRestoreCorrupted(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	writeTestHome(t, home1)
+	writeTestHome(t, home2)
+
+	strDir := filepath.Join(root, "snapshots")
+	opt := &types.SnapshotOption{Option: newTestOption(strConfig), Name: "snap1", Dir: strDir}
+	require.NoError(t, NewSnapshotManager(opt).Create())
+
+	strBundle := filepath.Join(strDir, "snap1.tar.gz")
+	f, err := os.OpenFile(strBundle, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("garbage"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	opt.Force = true
+	require.Error(t, NewSnapshotManager(opt).Restore())
+	require.True(t, utils.HasChainData(home1))
+}
+
+func TestSnapshotOverlayTemplate(t *testing.T) {
+	strTemplate := "validator_template:\n  count: 1\n  name: extra\n  home: \"{{ env \"SNAPSHOT_TEST_ROOT\" }}/extra%d\"\n  ip: \"127.0.0.1\"\n" +
+		"  coins: [\"1000uhby\"]\n  bonded: 100uhby\n  config:\n    consensus:\n      timeout_commit: \"1s\"\n    rpc:\n      laddr: \"tcp://0.0.0.0:46657\"\n    p2p:\n      laddr: \"tcp://0.0.0.0:46656\"\n"
+	root, strConfig := writeTestConfig(t, strTemplate)
+	t.Setenv("SNAPSHOT_TEST_ROOT", root)
+	for _, strName := range []string{"node1", "node2", "extra1"} {
+		writeTestHome(t, filepath.Join(root, strName))
+	}
+	strOverlay := filepath.Join(root, "overlay.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("client:\n  output: json\n"), 0o644))
+
+	strDir := filepath.Join(root, "snapshots")
+	opt := &types.SnapshotOption{Option: newTestOption(strConfig), Name: "snap1", Dir: strDir}
+	opt.ConfigOverlays = []string{strOverlay}
+	require.NoError(t, NewSnapshotManager(opt).Create())
+
+	restoreRoot := filepath.Join(root, "restored")
+	opt = &types.SnapshotOption{Option: newTestOption(strConfig), Name: "snap1", Dir: strDir, RestoreRoot: restoreRoot}
+	require.NoError(t, NewSnapshotManager(opt).Restore())
+	require.DirExists(t, filepath.Join(restoreRoot, "extra1", "config"))
+
+	//overlay is kept and template validator home is remapped
+	var ic types.IgniteConfig
+	data, err := os.ReadFile(strConfig)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &ic))
+	require.Equal(t, "json", ic.Client.Output)
+	require.Len(t, ic.Validators, 3)
+	require.Equal(t, filepath.Join(restoreRoot, "extra1"), ic.Validators[2].Home)
+	require.NotContains(t, string(data), "validator_template")
+	require.FileExists(t, strConfig+".bak")
+}
@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/shells"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/cosmos-cli/workspace"
+	"github.com/civet148/log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buildBinary builds node binary from source repository and uses it as node command of network. binaries
+// are cached by git commit and build settings, a dirty work tree is always rebuilt.
+func (m *ChainBuilder) buildBinary(ic *types.IgniteConfig) (err error) {
+	opt := m.option
+	if opt.FromSource == "" {
+		return nil
+	}
+	strRepo, err := filepath.Abs(workspace.ExpandHome(opt.FromSource))
+	if err != nil {
+		return log.Errorf("source repository %s error [%s]", opt.FromSource, err)
+	}
+	build := ic.Build
+	if len(opt.LDFlags) != 0 {
+		build.LDFlags = opt.LDFlags
+	}
+	if len(opt.BuildTags) != 0 {
+		build.Tags = opt.BuildTags
+	}
+	if opt.BuildOutput != "" {
+		build.Output = opt.BuildOutput
+	}
+	if build.Output == "" {
+		build.Output = filepath.Join(workspace.New("").Root(), types.WORKSPACE_BIN_SUBPATH)
+	}
+	if build.Main == "" {
+		build.Main = types.SOURCE_DEFAULT_MAIN
+	}
+	strBinary := build.Binary
+	if strBinary == "" {
+		strBinary = types.DEFAULT_NODE_CMD
+	}
+	strBinary = filepath.Base(strBinary)
+
+	cmd := utils.NewCmdExecutor(opt.Debug)
+	sm := shells.NewSourceMaker(strRepo)
+	var strCommit, strStatus string
+	if strCommit, err = cmd.Shell(sm.MakeCmdLineGitCommit()); err != nil {
+		return log.Errorf("source repository %s is not a git work tree [%s]", strRepo, err)
+	}
+	strCommit = utils.LastLine(strCommit)
+	if strStatus, err = cmd.Shell(sm.MakeCmdLineGitStatus()); err != nil {
+		return log.Errorf("git status of %s error [%s]", strRepo, err)
+	}
+	var dirty = strStatus != ""
+	strSettings := strings.Join([]string{build.Main, build.Make, strings.Join(build.LDFlags, " "), strings.Join(build.Tags, ",")}, "\n")
+	strKey := fmt.Sprintf("%.12s-%.8s", strCommit, utils.BytesSHA256([]byte(strSettings)))
+	if dirty {
+		strKey += types.SOURCE_DIRTY_SUFFIX
+	}
+	strDir := filepath.Join(workspace.ExpandHome(build.Output), strBinary, strKey)
+	strBin := filepath.Join(strDir, strBinary)
+
+	if _, err = os.Stat(strBin); err == nil && !dirty {
+		log.Infof("use cached node binary %s of commit %s", strBin, strCommit)
+	} else {
+		if dirty {
+			log.Warnf("source repository %s has uncommitted changes, binary is not cached", strRepo)
+		}
+		if err = os.MkdirAll(strDir, 0o755); err != nil {
+			return log.Errorf("make directory %s error [%s]", strDir, err)
+		}
+		var cmdline string
+		if build.Make != "" {
+			cmdline = sm.MakeCmdLineMakeInstall(build.Make, strDir, build.LDFlags, build.Tags)
+		} else {
+			cmdline = sm.MakeCmdLineGoBuild(build.Main, strBin, build.LDFlags, build.Tags)
+		}
+		if _, err = cmd.Shell(cmdline); err != nil {
+			return log.Errorf("build node binary from %s error [%s]", strRepo, err)
+		}
+		if _, err = os.Stat(strBin); err != nil {
+			return log.Errorf("node binary %s not found after build, please check build.binary of config file", strBin)
+		}
+	}
+	opt.NodeCmd = strBin
+	m.maker = m.maker.WithNodeCmd(strBin)
+	m.report.SourceRepo, m.report.SourceCommit = strRepo, strCommit
+	fmt.Printf("node binary %s built from %s commit %s\n", strBin, strRepo, strCommit)
+	return nil
+}
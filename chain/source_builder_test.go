@@ -0,0 +1,59 @@
+package chain
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBinaryFromSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root, strConfig := writeTestConfig(t, "build:\n  binary: hobbyd\n  main: ./cmd/hobbyd\n  ldflags: [\"-X main.Version=v1.0.0\"]\n")
+	strRepo := filepath.Join(root, "hobby")
+	require.NoError(t, os.MkdirAll(filepath.Join(strRepo, "cmd", "hobbyd"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(strRepo, "go.mod"), []byte("module hobby\n\ngo 1.18\n"), 0o644))
+	strMain := "package main\n\nimport \"fmt\"\n\nvar Version = \"dev\"\n\nfunc main() { fmt.Println(Version) }\n"
+	require.NoError(t, os.WriteFile(filepath.Join(strRepo, "cmd", "hobbyd", "main.go"), []byte(strMain), 0o644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@test", "commit", "-q", "-m", "init"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", strRepo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	opt := newTestOption(strConfig)
+	opt.FromSource = strRepo
+	opt.BuildOutput = filepath.Join(root, "bin")
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, b.buildBinary(ic))
+
+	strBin := opt.NodeCmd
+	require.Equal(t, strBin, b.maker.NodeCmd())
+	require.Contains(t, strBin, filepath.Join(root, "bin", "hobbyd"))
+	require.Len(t, b.report.SourceCommit, 40)
+	out, err := exec.Command(strBin).Output()
+	require.NoError(t, err)
+	require.Equal(t, "v1.0.0\n", string(out))
+
+	//cached binary of the same commit is reused
+	fi, err := os.Stat(strBin)
+	require.NoError(t, err)
+	require.NoError(t, b.buildBinary(ic))
+	fi2, err := os.Stat(strBin)
+	require.NoError(t, err)
+	require.Equal(t, fi.ModTime(), fi2.ModTime())
+
+	//other ldflags build another binary
+	opt.LDFlags = []string{"-X main.Version=v2.0.0"}
+	require.NoError(t, b.buildBinary(ic))
+	require.NotEqual(t, strBin, opt.NodeCmd)
+}
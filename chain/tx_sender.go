@@ -0,0 +1,218 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var gasPriceRegexp = regexp.MustCompile(`^[0-9.]+([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+
+// TxSender broadcasts bank send, staking delegate, gov vote and gov submit-proposal transactions to built network,
+// account names of config file are resolved to signer home, node RPC and gas prices.
+type TxSender struct {
+	option  *types.TxOption //tx option
+	builder *ChainBuilder   //chain builder of network
+}
+
+// txSigner is the key, home and node a transaction is signed and broadcast by
+type txSigner struct {
+	Name      string // account name
+	Home      string // home with keyring of account
+	Node      string // RPC endpoint
+	GasPrices string // gas prices
+}
+
+func NewTxSender(opt *types.TxOption) api.ManagerApi {
+	if opt == nil {
+		panic("tx option is nil")
+	}
+	return &TxSender{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+}
+
+func (m *TxSender) Run() (err error) {
+	if err = m.builder.openNetwork(); err != nil {
+		return err
+	}
+	err = m.send()
+	m.builder.journal(types.JOURNAL_CMD_TX, m.option.Action, err)
+	return err
+}
+
+func (m *TxSender) send() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	var nArgs = map[string]int{
+		types.TX_ACTION_SEND:            2,
+		types.TX_ACTION_DELEGATE:        2,
+		types.TX_ACTION_VOTE:            2,
+		types.TX_ACTION_SUBMIT_PROPOSAL: 1,
+	}
+	n, ok := nArgs[opt.Action]
+	if !ok {
+		return log.Errorf("unknown tx action %s", opt.Action)
+	}
+	if len(opt.Args) != n {
+		return log.Errorf("tx %s expects %d arguments but %d given", opt.Action, n, len(opt.Args))
+	}
+	b.probeDialects(ic)
+	var signers []*txSigner
+	if opt.From == "" && opt.Action == types.TX_ACTION_VOTE {
+		for _, v := range ic.Validators {
+			signers = append(signers, m.signer(ic, v.Name))
+		}
+	} else {
+		strFrom := opt.From
+		if strFrom == "" {
+			strFrom = b.strNode0Validator
+		}
+		if ic.GetAccountCoins(strFrom) == nil {
+			return log.Errorf("account [%s] not found in config file %s", strFrom, opt.ConfigPath)
+		}
+		signers = append(signers, m.signer(ic, strFrom))
+	}
+	for _, s := range signers {
+		var strCmdLine, output, strTxHash string
+		if strCmdLine, err = m.makeCmdLine(ic, s); err != nil {
+			return err
+		}
+		if output, err = utils.NewCmdExecutor(opt.Debug).Shell(strCmdLine); err != nil {
+			return log.Errorf("[%s] tx %s error [%s]", s.Name, opt.Action, err)
+		}
+		if strTxHash, err = parseTxResponse(output); err != nil {
+			return log.Errorf("[%s] tx %s error [%s]", s.Name, opt.Action, err)
+		}
+		fmt.Printf("[%s] tx %s broadcast txhash %s\n", s.Name, opt.Action, strTxHash)
+	}
+	return nil
+}
+
+// signer returns signer of account, a validator signs by its own home and node, other accounts by the first
+// validator (keys of accounts not being validators are added to its keyring by build)
+func (m *TxSender) signer(ic *types.IgniteConfig, strName string) *txSigner {
+	opt := m.option
+	v := m.signerValidator(ic, strName)
+	s := &txSigner{
+		Name:      strName,
+		Home:      v.Home,
+		Node:      opt.Node,
+		GasPrices: opt.GasPrices,
+	}
+	if s.Node == "" {
+		s.Node = ic.GetValidatorRPC(v.Name)
+	}
+	if s.GasPrices == "" && opt.Fees == "" {
+		s.GasPrices = pickGasPrice(v.App.MinimumGasPrices, opt.DefaultDenom)
+	}
+	return s
+}
+
+func (m *TxSender) makeCmdLine(ic *types.IgniteConfig, s *txSigner) (strCmdLine string, err error) {
+	opt := m.option
+	maker := m.builder.makerOf(m.signerValidator(ic, s.Name)).WithGasPrices(s.GasPrices)
+	switch opt.Action {
+	case types.TX_ACTION_SEND:
+		var strTo string
+		if strTo, err = m.address(ic, s, opt.Args[0], "acc"); err != nil {
+			return "", err
+		}
+		return maker.MakeCmdLineTxBankSend(s.Name, strTo, opt.Args[1], s.Home, s.Node, opt.Fees), nil
+	case types.TX_ACTION_DELEGATE:
+		var strValAddr string
+		if strValAddr, err = m.address(ic, s, opt.Args[0], "val"); err != nil {
+			return "", err
+		}
+		return maker.MakeCmdLineTxStakingDelegate(s.Name, strValAddr, opt.Args[1], s.Home, s.Node, opt.Fees), nil
+	case types.TX_ACTION_VOTE:
+		return maker.MakeCmdLineTxGovVote(opt.Args[0], opt.Args[1], s.Name, s.Home, s.Node, opt.Fees), nil
+	case types.TX_ACTION_SUBMIT_PROPOSAL:
+		strFile := opt.Args[0]
+		if strFile, err = filepath.Abs(strFile); err != nil {
+			return "", err
+		}
+		return maker.MakeCmdLineTxGovSubmitProposal(strFile, s.Name, s.Home, s.Node, opt.Fees), nil
+	}
+	return "", log.Errorf("unknown tx action %s", opt.Action)
+}
+
+// address resolves account name of config file to bech32 address of type (acc or val) by keyring of signer home,
+// an argument not being an account name is regarded as address already
+func (m *TxSender) address(ic *types.IgniteConfig, s *txSigner, strArg, strType string) (string, error) {
+	if ic.GetAccountCoins(strArg) == nil {
+		return strArg, nil
+	}
+	maker := m.builder.makerOf(m.signerValidator(ic, s.Name))
+	output, err := utils.NewCmdExecutor(m.option.Debug).Shell(maker.MakeCmdLineKeysShowAddrOnly(s.Home, strArg, strType))
+	if err != nil {
+		return "", log.Errorf("show %s address of account [%s] error [%s]", strType, strArg, err)
+	}
+	return utils.LastLine(output), nil
+}
+
+// signerValidator returns validator whose home and binary sign for account, the first validator for accounts not
+// being validators
+func (m *TxSender) signerValidator(ic *types.IgniteConfig, strName string) *types.ValidatorConfig {
+	_, v := ic.GetValidator(strName)
+	if v == nil {
+		_, v = ic.GetValidator(m.builder.strNode0Validator)
+	}
+	return v
+}
+
+// parseTxResponse parses JSON response of a transaction broadcast in sync mode and returns its hash, the CLI exits
+// 0 even if CheckTx rejected the transaction so a non-zero code is an error
+func parseTxResponse(output string) (string, error) {
+	var resp struct {
+		TxHash    string `json:"txhash"`
+		Code      uint32 `json:"code"`
+		Codespace string `json:"codespace"`
+		RawLog    string `json:"raw_log"`
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		strLine := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(strLine, "{") || json.Unmarshal([]byte(strLine), &resp) != nil || resp.TxHash == "" {
+			continue
+		}
+		if resp.Code != 0 {
+			return resp.TxHash, fmt.Errorf("tx %s failed with code %d (%s) %s", resp.TxHash, resp.Code, resp.Codespace, resp.RawLog)
+		}
+		return resp.TxHash, nil
+	}
+	return "", fmt.Errorf("no tx response in output: %s", utils.LastLine(output))
+}
+
+// pickGasPrice picks gas price of denom from minimum gas prices, eg. 10000000usby,10000000uhby => 10000000uhby,
+// the first gas price if denom not found
+func pickGasPrice(strMinGasPrices, strDenom string) string {
+	var prices []string
+	for _, strPrice := range strings.Split(strMinGasPrices, ",") {
+		if strPrice = strings.TrimSpace(strPrice); strPrice == "" {
+			continue
+		}
+		if sub := gasPriceRegexp.FindStringSubmatch(strPrice); sub != nil && sub[1] == strDenom {
+			return strPrice
+		}
+		prices = append(prices, strPrice)
+	}
+	if len(prices) == 0 {
+		return ""
+	}
+	return prices[0]
+}
@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickGasPrice(t *testing.T) {
+	require.Equal(t, "10000000uhby", pickGasPrice("10000000usby, 10000000uhby", "uhby"))
+	require.Equal(t, "10000000usby", pickGasPrice("10000000usby,10000000uhby", "aevmos"))
+	require.Equal(t, "0.025ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2", pickGasPrice("0.025ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2", "uhby"))
+	require.Equal(t, "", pickGasPrice("", "uhby"))
+}
+
+func TestParseTxResponse(t *testing.T) {
+	strHash := "A1B2C3D4E5F60718293A4B5C6D7E8F90A1B2C3D4E5F60718293A4B5C6D7E8F90"
+	strTxHash, err := parseTxResponse("gas estimate: 81234\n{\"height\":\"0\",\"txhash\":\"" + strHash + "\",\"code\":0,\"raw_log\":\"[]\"}\n")
+	require.NoError(t, err)
+	require.Equal(t, strHash, strTxHash)
+
+	//sync broadcast exits 0 when CheckTx rejected the transaction
+	strTxHash, err = parseTxResponse("{\"txhash\":\"" + strHash + "\",\"codespace\":\"sdk\",\"code\":13,\"raw_log\":\"insufficient fee\"}")
+	require.Error(t, err)
+	require.Equal(t, strHash, strTxHash)
+	require.Contains(t, err.Error(), "code 13 (sdk) insufficient fee")
+
+	_, err = parseTxResponse("Error: account sequence mismatch")
+	require.Error(t, err)
+}
+
+func TestTxSender(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	strOverlay := filepath.Join(root, "gas.yml")
+	require.NoError(t, os.WriteFile(strOverlay, []byte("validators:\n- name: validator2\n  app:\n    minimum-gas-prices: \"10usby,20uhby\"\n"), 0o644))
+
+	opt := &types.TxOption{Option: newTestOption(strConfig), Action: types.TX_ACTION_VOTE, Args: []string{"1", "yes"}}
+	opt.ConfigOverlays = []string{strOverlay}
+	opt.DefaultDenom = "uhby"
+	m := NewTxSender(opt).(*TxSender)
+	ic, err := m.builder.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, m.builder.checkConfig(ic))
+
+	s := m.signer(ic, "validator2")
+	require.Equal(t, &txSigner{Name: "validator2", Home: home2, Node: "tcp://127.0.0.1:36657", GasPrices: "20uhby"}, s)
+	strCmdLine, err := m.makeCmdLine(ic, s)
+	require.NoError(t, err)
+	require.Equal(t, "hobbyd tx gov vote 1 yes --from validator2 --chain-id test_9000-1 --home "+home2+" --keyring-backend test "+
+		"--gas-prices 20uhby --node tcp://127.0.0.1:36657 --gas auto --gas-adjustment 1.5 --broadcast-mode sync --output json -y", strCmdLine)
+
+	//fees and node flags win, send to an address needs no key lookup
+	opt.Fees, opt.Node, opt.Action, opt.Args = "100uhby", "tcp://10.0.0.1:26657", types.TX_ACTION_SEND, []string{"hby1qqqq", "5uhby"}
+	s = m.signer(ic, "validator1")
+	require.Equal(t, &txSigner{Name: "validator1", Home: home1, Node: "tcp://10.0.0.1:26657"}, s)
+	strCmdLine, err = m.makeCmdLine(ic, s)
+	require.NoError(t, err)
+	require.Contains(t, strCmdLine, "hobbyd tx bank send validator1 hby1qqqq 5uhby --chain-id test_9000-1 --home "+home1+" --keyring-backend test --fees 100uhby --node tcp://10.0.0.1:26657")
+}
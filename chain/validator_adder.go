@@ -0,0 +1,257 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"os"
+	"strings"
+	"time"
+)
+
+// ValidatorAdder joins a new validator declared in config file to an existing network
+type ValidatorAdder struct {
+	option  *types.ValidatorOption //validator option
+	builder *ChainBuilder          //chain builder of existing network
+}
+
+func NewValidatorAdder(opt *types.ValidatorOption) api.ManagerApi {
+	if opt == nil {
+		panic("validator option is nil")
+	}
+	return &ValidatorAdder{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+}
+
+func (m *ValidatorAdder) Run() (err error) {
+	if err = m.builder.openNetwork(); err != nil {
+		return err
+	}
+	err = m.addValidator()
+	m.builder.journal(types.JOURNAL_CMD_VALIDATOR_ADD, m.option.Name, err)
+	return err
+}
+
+func (m *ValidatorAdder) addValidator() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	b.probeDialects(ic)
+	idx, v := ic.GetValidator(m.option.Name)
+	if v == nil {
+		return log.Errorf("validator [%s] not found in config file %s", m.option.Name, m.option.ConfigPath)
+	}
+	if v.Name == b.strNode0Validator {
+		return log.Errorf("validator [%s] is the first validator of network", v.Name)
+	}
+	if err = m.initNode(v); err != nil {
+		return err
+	}
+	if err = m.configurePeers(ic, v); err != nil {
+		return err
+	}
+	if err = b.updateValidatorAppConfig(idx, v); err != nil {
+		return err
+	}
+	if err = b.updateValidatorCosmosConfig(idx, v); err != nil {
+		return err
+	}
+	if err = b.updateValidatorClientConfig(ic, idx, v); err != nil {
+		return err
+	}
+	return m.createValidator(ic, v)
+}
+
+// initNode initializes new validator home with the key and genesis file of existing network
+func (m *ValidatorAdder) initNode(v *types.ValidatorConfig) (err error) {
+	b := m.builder
+	maker := b.makerOf(v)
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	passwd := m.option.KeyringBackend != types.KEYRING_BACKEND_TEST
+
+	if _, err = os.Stat(utils.MakeCosmosConfigPath(v.Home, types.FILE_NAME_GENESIS)); err == nil {
+		return log.Errorf("validator [%s] home %s already initialized", v.Name, v.Home)
+	}
+	var cmdlines = []string{
+		maker.MakeCmdLineInit(v.Config.Moniker, v.Home),
+		//add validator account key to first validator keyring and share it with new validator
+		maker.MakeCmdLineKeysAdd(v.Name, b.strNode0Home, false, passwd),
+		maker.MakeCmdLineMkdirKeyringFile(v.Home),
+		maker.MakeCmdLineCopyKeysFile(b.strNode0Home, v.Home),
+		//genesis file of existing network
+		maker.MakeCmdLineCopyGenesisFile(b.strNode0Home, v.Home),
+	}
+	for _, cmdline := range cmdlines {
+		if _, err = cmd.Shell(cmdline); err != nil {
+			return log.Errorf(err.Error())
+		}
+	}
+	return nil
+}
+
+// configurePeers makes persistent peers of new validator from node ids of existing validators and adds new
+// validator to persistent peers of existing validators
+func (m *ValidatorAdder) configurePeers(ic *types.IgniteConfig, v *types.ValidatorConfig) (err error) {
+	b := m.builder
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	var existing []types.ValidatorConfig
+	for _, e := range ic.Validators {
+		if e.Name == v.Name {
+			continue
+		}
+		if _, err = os.Stat(utils.MakeCosmosConfigPath(e.Home, types.FILE_NAME_GENESIS)); err != nil {
+			log.Warnf("validator [%s] home %s not initialized, skip it", e.Name, e.Home)
+			continue
+		}
+		var np *types.NodePeer
+		if np, err = b.makeNodePeer(cmd, ic, e.Name, e.Home); err != nil {
+			return err
+		}
+		b.peers[e.Name] = np
+		existing = append(existing, e)
+	}
+	if len(existing) == 0 {
+		return log.Errorf("no existing validator found to peer with")
+	}
+	var np *types.NodePeer
+	if np, err = b.makeNodePeer(cmd, ic, v.Name, v.Home); err != nil {
+		return err
+	}
+	np.PersistentPeers = b.makePersistentPeers(v.Name)
+	b.peers[v.Name] = np
+	log.Infof("validator [%s] peer %s", v.Name, np.Peer)
+	for i := range existing {
+		if err = m.addPersistentPeer(&existing[i], np.Peer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPersistentPeer adds peer to p2p.persistent_peers of existing validator config, a peer of the same node id is
+// replaced. the existing node dials the new validator after restart.
+func (m *ValidatorAdder) addPersistentPeer(e *types.ValidatorConfig, strPeer string) (err error) {
+	var conf struct {
+		P2P struct {
+			PersistentPeers string `toml:"persistent_peers"`
+		} `toml:"p2p"`
+	}
+	strPath := utils.MakeCosmosConfigPath(e.Home, types.FILE_NAME_CONFIG)
+	if err = confile.New(confile.DefaultTOMLEncodingCreator, strPath).Load(&conf); err != nil {
+		return log.Errorf("load config [%s] error [%s]", strPath, err.Error())
+	}
+	strNodeId := strings.Split(strPeer, "@")[0]
+	var peers []string
+	for _, strOld := range strings.Split(conf.P2P.PersistentPeers, ",") {
+		if strOld = strings.TrimSpace(strOld); strOld == "" || strings.Split(strOld, "@")[0] == strNodeId {
+			continue
+		}
+		peers = append(peers, strOld)
+	}
+	strPeers := strings.Join(append(peers, strPeer), ",")
+	if strPeers == conf.P2P.PersistentPeers {
+		return nil
+	}
+	values := map[string]interface{}{
+		"p2p": map[string]interface{}{"persistent_peers": strPeers},
+	}
+	//config of a running validator is kept as config.toml.bak
+	if err = confile.New(confile.DefaultTOMLEncodingCreator, strPath).WithBackup().EditTOML(values); err != nil {
+		return log.Errorf("update config [%s] error [%s]", strPath, err.Error())
+	}
+	log.Infof("[%s] p2p.persistent_peers=%s, restart it to dial the new validator", e.Name, strPeers)
+	return nil
+}
+
+// createValidator funds the new validator account and emits its create-validator transaction.
+// transactions are broadcast when a RPC node is specified, otherwise unsigned transactions are saved to validator home.
+func (m *ValidatorAdder) createValidator(ic *types.IgniteConfig, v *types.ValidatorConfig) (err error) {
+	var strAddr, strPubKey, output string
+	b := m.builder
+	opt := m.option
+	maker := b.makerOf(v)
+	cmd := utils.NewCmdExecutor(opt.Debug)
+
+	if output, err = cmd.Shell(maker.MakeCmdLineKeysShowAddrOnly(v.Home, v.Name, "acc")); err != nil {
+		return log.Errorf(err.Error())
+	}
+	strAddr = utils.LastLine(output)
+	if output, err = cmd.Shell(maker.MakeCmdLineShowValidator(v.Home)); err != nil {
+		return log.Errorf(err.Error())
+	}
+	strPubKey = utils.LastLine(output)
+
+	strFrom := opt.From
+	if strFrom == "" {
+		strFrom = b.strNode0Validator
+	}
+	strCoins := ic.GetAccountBalances(v.Name)
+	output, err = cmd.Shell(maker.MakeCmdLineTxBankSend(strFrom, strAddr, strCoins, b.strNode0Home, opt.Node, opt.Fees))
+	if err != nil {
+		return log.Errorf(err.Error())
+	}
+	if err = m.emitTx(v, types.FILE_NAME_FUND_TX, output); err != nil {
+		return err
+	}
+	if opt.Node != "" {
+		if err = m.waitFunded(strAddr); err != nil {
+			return err
+		}
+	}
+	output, err = cmd.Shell(maker.MakeCmdLineTxCreateValidator(v.Name, v.Home, v.Bonded, strPubKey, v.Config.Moniker, opt.Node, opt.Fees))
+	if err != nil {
+		return log.Errorf(err.Error())
+	}
+	if err = m.emitTx(v, types.FILE_NAME_CREATE_VALIDATOR_TX, output); err != nil {
+		return err
+	}
+	fmt.Printf("[%s] %s joined with node %s\n", v.Name, strAddr, b.peers[v.Name].Peer)
+	return nil
+}
+
+// emitTx prints broadcast result or saves generated unsigned transaction into validator config path
+func (m *ValidatorAdder) emitTx(v *types.ValidatorConfig, strFileName, output string) error {
+	strTx := utils.LastLine(output)
+	if m.option.Node != "" {
+		log.Infof("[%s] broadcast tx result %s", v.Name, strTx)
+		return nil
+	}
+	strPath := utils.MakeCosmosConfigPath(v.Home, strFileName)
+	if err := os.WriteFile(strPath, []byte(strTx), 0o644); err != nil {
+		return log.Errorf("write unsigned tx %s error [%s]", strPath, err)
+	}
+	fmt.Printf("[%s] unsigned tx saved to %s, please sign and broadcast it\n", v.Name, strPath)
+	return nil
+}
+
+// waitFunded polls account balances until the funding transaction committed
+func (m *ValidatorAdder) waitFunded(strAddr string) error {
+	type balances struct {
+		Balances []interface{} `json:"balances"`
+	}
+	cmd := utils.NewCmdExecutor(m.option.Debug)
+	cmdline := m.builder.maker.MakeCmdLineQueryBalances(strAddr, m.option.Node)
+	deadline := time.Now().Add(types.QUERY_POLL_TIMEOUT * time.Second)
+	for time.Now().Before(deadline) {
+		output, err := cmd.Shell(cmdline)
+		if err == nil {
+			var b balances
+			if err = json.Unmarshal([]byte(utils.LastLine(output)), &b); err == nil && len(b.Balances) != 0 {
+				return nil
+			}
+		}
+		time.Sleep(types.QUERY_POLL_INTERVAL * time.Second)
+	}
+	return log.Errorf("account %s not funded after %d seconds", strAddr, types.QUERY_POLL_TIMEOUT)
+}
@@ -0,0 +1,104 @@
+package chain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// testValidatorScript is a fake node binary logging its arguments and answering the commands of validator add
+const testValidatorScript = `#!/bin/sh
+echo "$@" >> %s
+home=""; prev=""
+for a in "$@"; do
+  [ "$prev" = "--home" ] && home="$a"
+  prev="$a"
+done
+case "$*" in
+init*) mkdir -p "$home/config" && printf '[p2p]\npersistent_peers = ""\n' > "$home/config/config.toml" && echo '{}' > "$home/config/genesis.json" ;;
+"keys add"*) echo "added $3" ;;
+"keys show"*) echo "hby1$3$5" ;;
+*show-node-id*) echo "id$(basename "$home")" ;;
+*show-validator*) echo '{"@type":"/cosmos.crypto.ed25519.PubKey","key":"PK"}' ;;
+"tx bank send"*) echo '{"body":{"messages":[{"@type":"/cosmos.bank.v1beta1.MsgSend"}]}}' ;;
+"tx staking create-validator"*) echo '{"body":{"messages":[{"@type":"/cosmos.staking.v1beta1.MsgCreateValidator"}]}}' ;;
+esac
+`
+
+func TestValidatorAdder(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	strLog := filepath.Join(root, "cmd.log")
+	strBin := filepath.Join(root, "bin", "hobbyd")
+	require.NoError(t, os.MkdirAll(filepath.Dir(strBin), 0o755))
+	require.NoError(t, os.WriteFile(strBin, []byte(fmt.Sprintf(testValidatorScript, strLog)), 0o755))
+	//validator1 is the existing network
+	require.NoError(t, os.MkdirAll(filepath.Join(home1, "config"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(home1, "keyring-test"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home1, "keyring-test", "validator2.info"), []byte("key"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(home1, "config", "genesis.json"), []byte(`{"chain_id":"test_9000-1"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(home1, "config", "config.toml"),
+		[]byte("moniker = \"node1\"\n\n[p2p]\npersistent_peers = \"idold@10.0.0.9:26656\"\n"), 0o644))
+
+	opt := &types.ValidatorOption{Option: newTestOption(strConfig), Name: "validator2"}
+	opt.NodeCmd = strBin
+	m := NewValidatorAdder(opt).(*ValidatorAdder)
+	ic, err := m.builder.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, m.builder.checkConfig(ic))
+	_, v := ic.GetValidator("validator2")
+	readLog := func() string {
+		data, err := os.ReadFile(strLog)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(strLog))
+		return string(data)
+	}
+
+	//new home gets validator key from the keyring of first validator and genesis of existing network
+	require.NoError(t, m.initNode(v))
+	require.Contains(t, readLog(), "keys add validator2 --home "+home1+" --keyring-backend test\n")
+	data, err := os.ReadFile(filepath.Join(home2, "config", "genesis.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{"chain_id":"test_9000-1"}`, string(data))
+	require.FileExists(t, filepath.Join(home2, "keyring-test", "validator2.info"))
+	require.Error(t, m.initNode(v))
+
+	//new validator peers with existing validators and is added to their persistent peers
+	require.NoError(t, m.configurePeers(ic, v))
+	require.Equal(t, "idnode2@127.0.0.1:36656", m.builder.peers["validator2"].Peer)
+	require.Equal(t, []string{"idnode1@127.0.0.1:26656"}, m.builder.peers["validator2"].PersistentPeers)
+	strToml := filepath.Join(home1, "config", "config.toml")
+	data, err = os.ReadFile(strToml)
+	require.NoError(t, err)
+	require.Equal(t, "moniker = \"node1\"\n\n[p2p]\npersistent_peers = \"idold@10.0.0.9:26656,idnode2@127.0.0.1:36656\"\n", string(data))
+	//config of the running validator is backed up
+	bak, err := os.ReadFile(strToml + confile.BackupSuffix)
+	require.NoError(t, err)
+	require.Equal(t, "moniker = \"node1\"\n\n[p2p]\npersistent_peers = \"idold@10.0.0.9:26656\"\n", string(bak))
+	require.NoError(t, m.configurePeers(ic, v))
+	data2, err := os.ReadFile(strToml)
+	require.NoError(t, err)
+	require.Equal(t, string(data), string(data2))
+	readLog()
+
+	//no RPC node: funding and create-validator transactions are generated only and saved to new home
+	require.NoError(t, m.createValidator(ic, v))
+	strCmds := readLog()
+	require.Contains(t, strCmds, "tx bank send validator1 hby1validator2acc 1000uhby --chain-id test_9000-1 --home "+home1+" --keyring-backend test --generate-only\n")
+	require.Contains(t, strCmds, "tx staking create-validator --from validator2 --amount 100uhby "+
+		`--pubkey {"@type":"/cosmos.crypto.ed25519.PubKey","key":"PK"} --moniker node2 `)
+	require.True(t, strings.HasSuffix(strCmds, "--home "+home2+" --keyring-backend test --generate-only\n"))
+	data, err = os.ReadFile(utils.MakeCosmosConfigPath(home2, types.FILE_NAME_FUND_TX))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "MsgSend")
+	data, err = os.ReadFile(utils.MakeCosmosConfigPath(home2, types.FILE_NAME_CREATE_VALIDATOR_TX))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "MsgCreateValidator")
+}
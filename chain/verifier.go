@@ -0,0 +1,165 @@
+package chain
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/confile"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/log"
+	"github.com/pelletier/go-toml"
+	"sort"
+	"strings"
+)
+
+// NetworkVerifier checks every validator home of a built network shares the identical genesis and
+// persistent peers reference real node IDs of the network.
+type NetworkVerifier struct {
+	option  *types.Option //verify option
+	builder *ChainBuilder //chain builder of network
+}
+
+func NewNetworkVerifier(opt *types.Option) api.ManagerApi {
+	if opt == nil {
+		panic("verify option is nil")
+	}
+	return &NetworkVerifier{
+		option:  opt,
+		builder: NewChainBuilder(opt).(*ChainBuilder),
+	}
+}
+
+func (m *NetworkVerifier) Run() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	err = b.verifyNetwork(ic)
+	b.journal(types.JOURNAL_CMD_VERIFY, types.STAGE_VERIFY, err)
+	return err
+}
+
+// homeState is the state of a validator home to be compared with others
+type homeState struct {
+	Name          string
+	GenesisHash   string
+	ChainID       string
+	InitialHeight string
+	GenTxs        []string //sorted SHA-256 of canonical gentx
+	NodeID        string
+	Peers         []string //persistent peers of config.toml
+}
+
+// verifyNetwork prints divergences of validator homes and fails if any
+func (m *ChainBuilder) verifyNetwork(ic *types.IgniteConfig) (err error) {
+	var issues []string
+	var strHash string
+	if issues, strHash, err = m.verifyHomes(ic); err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Printf("network verified, %d validators share genesis sha256 %s\n", len(ic.Validators), strHash)
+		return nil
+	}
+	for _, s := range issues {
+		fmt.Printf("  - %s\n", s)
+	}
+	return log.Errorf("network verification failed with %d issue(s)", len(issues))
+}
+
+// verifyHomes returns divergences of validator homes compared with the first validator and the genesis
+// hash of the first validator
+func (m *ChainBuilder) verifyHomes(ic *types.IgniteConfig) (issues []string, strHash string, err error) {
+	var states []*homeState
+	var nodes = make(map[string]string) //node id => validator name
+	for _, v := range ic.Validators {
+		var s *homeState
+		if s, err = loadHomeState(v.Name, v.Home); err != nil {
+			issues = append(issues, fmt.Sprintf("validator [%s] %s", v.Name, err))
+			continue
+		}
+		states = append(states, s)
+		nodes[s.NodeID] = s.Name
+	}
+	if len(states) == 0 {
+		return issues, "", nil
+	}
+	ref := states[0]
+	if ic.Genesis.ChainID != "" && ref.ChainID != ic.Genesis.ChainID {
+		issues = append(issues, fmt.Sprintf("validator [%s] genesis chain_id %s differs from config file %s", ref.Name, ref.ChainID, ic.Genesis.ChainID))
+	}
+	for _, s := range states {
+		if s != ref {
+			if s.GenesisHash != ref.GenesisHash {
+				issues = append(issues, fmt.Sprintf("validator [%s] genesis sha256 %s differs from [%s] %s", s.Name, s.GenesisHash, ref.Name, ref.GenesisHash))
+			}
+			if s.ChainID != ref.ChainID {
+				issues = append(issues, fmt.Sprintf("validator [%s] genesis chain_id %s differs from [%s] %s", s.Name, s.ChainID, ref.Name, ref.ChainID))
+			}
+			if s.InitialHeight != ref.InitialHeight {
+				issues = append(issues, fmt.Sprintf("validator [%s] genesis initial_height %s differs from [%s] %s", s.Name, s.InitialHeight, ref.Name, ref.InitialHeight))
+			}
+			if strings.Join(s.GenTxs, ",") != strings.Join(ref.GenTxs, ",") {
+				issues = append(issues, fmt.Sprintf("validator [%s] genesis has %d gentxs different from %d gentxs of [%s]", s.Name, len(s.GenTxs), len(ref.GenTxs), ref.Name))
+			}
+		}
+		for _, strPeer := range s.Peers {
+			strID := strings.ToLower(strings.SplitN(strPeer, "@", 2)[0])
+			strName, ok := nodes[strID]
+			switch {
+			case !ok:
+				issues = append(issues, fmt.Sprintf("validator [%s] persistent peer %s references unknown node id", s.Name, strPeer))
+			case strName == s.Name:
+				issues = append(issues, fmt.Sprintf("validator [%s] persistent peer %s references itself", s.Name, strPeer))
+			}
+		}
+	}
+	return issues, ref.GenesisHash, nil
+}
+
+func loadHomeState(strName, strHome string) (s *homeState, err error) {
+	s = &homeState{Name: strName}
+	strGenesis := utils.MakeCosmosConfigPath(strHome, types.FILE_NAME_GENESIS)
+	if s.GenesisHash, err = utils.FileSHA256(strGenesis); err != nil {
+		return nil, fmt.Errorf("hash genesis error [%s]", err)
+	}
+	var genesis map[string]interface{}
+	if genesis, err = loadGenesisFile(strGenesis); err != nil {
+		return nil, err
+	}
+	s.ChainID = fmt.Sprintf("%v", genesis["chain_id"])
+	s.InitialHeight = fmt.Sprintf("%v", genesis["initial_height"])
+	appState, _ := genesis["app_state"].(map[string]interface{})
+	genutil, _ := appState["genutil"].(map[string]interface{})
+	gentxs, _ := genutil["gen_txs"].([]interface{})
+	for _, tx := range gentxs {
+		var data []byte
+		if data, err = confile.MarshalCanonicalJSON(tx); err != nil {
+			return nil, fmt.Errorf("encode gentx error [%s]", err)
+		}
+		s.GenTxs = append(s.GenTxs, utils.BytesSHA256(data))
+	}
+	sort.Strings(s.GenTxs)
+	if s.NodeID, err = utils.LoadNodeID(utils.MakeCosmosConfigPath(strHome, types.FILE_NAME_NODE_KEY)); err != nil {
+		return nil, err
+	}
+	strConfig := utils.MakeCosmosConfigPath(strHome, types.FILE_NAME_CONFIG)
+	var tree *toml.Tree
+	if tree, err = toml.LoadFile(strConfig); err != nil {
+		return nil, fmt.Errorf("load config %s error [%s]", strConfig, err)
+	}
+	strPeers, _ := tree.Get("p2p.persistent_peers").(string)
+	for _, strPeer := range strings.Split(strPeers, ",") {
+		if strPeer = strings.TrimSpace(strPeer); strPeer != "" {
+			s.Peers = append(s.Peers, strPeer)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const testVerifyGenesis = `{"chain_id":"%s","initial_height":"1","app_state":{"genutil":{"gen_txs":[{"body":{"memo":"a"}},{"body":{"memo":"b"}}]}}}`
+
+// writeVerifyHome writes genesis, node key and persistent peers of home and returns its node id
+func writeVerifyHome(t *testing.T, strHome, strChainID string) string {
+	require.NoError(t, os.MkdirAll(filepath.Join(strHome, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(strHome, "config", "genesis.json"), []byte(fmt.Sprintf(testVerifyGenesis, strChainID)), 0o644))
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	strKey := fmt.Sprintf(`{"priv_key":{"type":"tendermint/PrivKeyEd25519","value":"%s"}}`, base64.StdEncoding.EncodeToString(priv))
+	strKeyPath := filepath.Join(strHome, "config", "node_key.json")
+	require.NoError(t, os.WriteFile(strKeyPath, []byte(strKey), 0o600))
+	strID, err := utils.LoadNodeID(strKeyPath)
+	require.NoError(t, err)
+	return strID
+}
+
+func writeVerifyPeers(t *testing.T, strHome string, peers string) {
+	strConfig := fmt.Sprintf("# comment\n[p2p]\npersistent_peers = \"%s\"\n", peers)
+	require.NoError(t, os.WriteFile(filepath.Join(strHome, "config", "config.toml"), []byte(strConfig), 0o644))
+}
+
+func TestVerifyHomes(t *testing.T) {
+	root, strConfig := writeTestConfig(t, "")
+	home1, home2 := filepath.Join(root, "node1"), filepath.Join(root, "node2")
+	id1 := writeVerifyHome(t, home1, "test_9000-1")
+	id2 := writeVerifyHome(t, home2, "test_9000-1")
+	writeVerifyPeers(t, home1, id2+"@127.0.0.1:36656")
+	writeVerifyPeers(t, home2, id1+"@127.0.0.1:26656")
+
+	opt := newTestOption(strConfig)
+	b := NewChainBuilder(&opt).(*ChainBuilder)
+	ic, err := b.parseConfig()
+	require.NoError(t, err)
+	require.NoError(t, b.checkConfig(ic))
+
+	issues, strHash, err := b.verifyHomes(ic)
+	require.NoError(t, err)
+	require.Empty(t, issues)
+	strExpected, err := utils.FileSHA256(filepath.Join(home1, "config", "genesis.json"))
+	require.NoError(t, err)
+	require.Equal(t, strExpected, strHash)
+	require.NoError(t, NewNetworkVerifier(&opt).Run())
+
+	//diverged chain id, a dropped gentx, unknown and self referencing peers
+	strGenesis := `{"chain_id":"other","initial_height":"1","app_state":{"genutil":{"gen_txs":[{"body":{"memo":"a"}}]}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(home2, "config", "genesis.json"), []byte(strGenesis), 0o644))
+	writeVerifyPeers(t, home1, "0000000000000000000000000000000000000000@127.0.0.1:36656,"+id1+"@127.0.0.1:26656")
+	issues, _, err = b.verifyHomes(ic)
+	require.NoError(t, err)
+	require.Len(t, issues, 5)
+	require.Error(t, b.verifyNetwork(ic))
+
+	//missing home is a divergence as well
+	require.NoError(t, os.RemoveAll(home2))
+	issues, _, err = b.verifyHomes(ic)
+	require.NoError(t, err)
+	require.Contains(t, issues[0], "validator2")
+}
@@ -0,0 +1,105 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/civet148/cosmos-cli/api"
+	"github.com/civet148/cosmos-cli/rpc"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/log"
+)
+
+// NetworkWaiter polls RPC endpoints of validators in config file until all (or a quorum of) validators reach
+// a target height and are not catching up. It replaces fixed sleeps of CI pipelines after the network started.
+type NetworkWaiter struct {
+	option  *types.WaitOption //wait option
+	builder *ChainBuilder     //chain builder of network
+}
+
+func NewNetworkWaiter(opt *types.WaitOption) api.ManagerApi {
+	if opt == nil {
+		panic("wait option is nil")
+	}
+	return &NetworkWaiter{
+		option:  opt,
+		builder: NewChainBuilder(&opt.Option).(*ChainBuilder),
+	}
+}
+
+func (m *NetworkWaiter) Run() (err error) {
+	var ic *types.IgniteConfig
+	b := m.builder
+	opt := m.option
+	if err = b.openNetwork(); err != nil {
+		return err
+	}
+	if ic, err = b.parseConfig(); err != nil {
+		return err
+	}
+	if err = b.checkConfig(ic); err != nil {
+		return err
+	}
+	if opt.Quorum <= 0 {
+		opt.Quorum = len(ic.Validators)
+	}
+	if opt.Quorum > len(ic.Validators) {
+		return log.Errorf("quorum %d is more than %d validators", opt.Quorum, len(ic.Validators))
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = types.WAIT_DEFAULT_INTERVAL * time.Second
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = types.WAIT_DEFAULT_TIMEOUT * time.Second
+	}
+	var nodes []*nodeHealth
+	for _, v := range ic.Validators {
+		strRPC := ic.GetValidatorRPC(v.Name)
+		nodes = append(nodes, &nodeHealth{Name: v.Name, RPC: strRPC, client: rpc.NewClient(strRPC)})
+	}
+	start := time.Now()
+	deadline := start.Add(opt.Timeout)
+	//a node hanging on RPC must not hold the wait beyond the deadline
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	for {
+		var states []string
+		if states = m.poll(ctx, nodes); len(states) == 0 {
+			fmt.Printf("%d/%d validators reached height %d in %s\n", opt.Quorum, len(nodes), opt.Height, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		//no time left for another poll, give up with the last states at once
+		if time.Until(deadline) > opt.Interval {
+			time.Sleep(opt.Interval)
+			continue
+		}
+		return log.Errorf("timeout after %s waiting for %d/%d validators to reach height %d: %s",
+			opt.Timeout, opt.Quorum, len(nodes), opt.Height, strings.Join(states, "; "))
+	}
+}
+
+// poll queries status of every node once and returns states of nodes not ready, nil if quorum of nodes are ready
+func (m *NetworkWaiter) poll(ctx context.Context, nodes []*nodeHealth) (states []string) {
+	opt := m.option
+	var nReady int
+	for _, n := range nodes {
+		status, err := n.client.Status(ctx)
+		switch {
+		case err != nil:
+			states = append(states, fmt.Sprintf("validator [%s] %s unreachable [%s]", n.Name, n.RPC, err))
+		case status.SyncInfo.CatchingUp:
+			states = append(states, fmt.Sprintf("validator [%s] catching up at height %d", n.Name, status.SyncInfo.LatestBlockHeight))
+		case status.SyncInfo.LatestBlockHeight < opt.Height:
+			states = append(states, fmt.Sprintf("validator [%s] at height %d", n.Name, status.SyncInfo.LatestBlockHeight))
+		default:
+			nReady++
+		}
+	}
+	if nReady >= opt.Quorum {
+		return nil
+	}
+	log.Debugf("%d/%d validators ready, waiting for %d", nReady, len(nodes), opt.Quorum)
+	return states
+}
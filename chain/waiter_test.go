@@ -0,0 +1,44 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkWaiter(t *testing.T) {
+	strConfig, fakes := newFakeNetwork(t)
+	fakes[1].set(func(f *fakeRPC) { f.height, f.catchingUp = 3, true })
+
+	opt := &types.WaitOption{Option: newTestOption(strConfig), Height: 5, Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond}
+	err := NewNetworkWaiter(opt).Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "waiting for 2/2 validators to reach height 5: validator [validator2] catching up at height 3")
+
+	//quorum of one validator is ready already
+	opt.Quorum = 1
+	require.NoError(t, NewNetworkWaiter(opt).Run())
+	opt.Quorum = 3
+	require.Error(t, NewNetworkWaiter(opt).Run())
+
+	//all validators reach the height while waiting
+	opt.Quorum, opt.Timeout = 0, 5*time.Second
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		fakes[1].set(func(f *fakeRPC) { f.height, f.catchingUp = 4, false })
+		time.Sleep(30 * time.Millisecond)
+		fakes[1].set(func(f *fakeRPC) { f.height = 6 })
+	}()
+	require.NoError(t, NewNetworkWaiter(opt).Run())
+
+	//a hanging node and a long interval do not hold the wait beyond the timeout
+	fakes[1].set(func(f *fakeRPC) { f.delay = 10 * time.Second })
+	opt.Interval, opt.Timeout = 10*time.Second, 100*time.Millisecond
+	start := time.Now()
+	err = NewNetworkWaiter(opt).Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validator [validator2]")
+	require.Less(t, time.Since(start), 2*time.Second)
+}
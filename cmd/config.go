@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/config"
+	"github.com/civet148/cosmos-cli/workspace"
+	"github.com/urfave/cli/v2"
+)
+
+var configCmd = &cli.Command{
+	Name:  CMD_NAME_CONFIG,
+	Usage: "config file tools",
+	Subcommands: []*cli.Command{
+		configRenderCmd,
+	},
+}
+
+var configRenderCmd = &cli.Command{
+	Name:  CMD_NAME_RENDER,
+	Usage: "print the effective config rendered from config file and overlays",
+	Flags: initFlags,
+	Action: func(cctx *cli.Context) error {
+		opt := newOption(cctx)
+		if opt.Network != "" {
+			opt.ConfigPath = workspace.New("").Network(opt.Network).ConfigPath()
+		}
+		data, err := config.Load(append([]string{opt.ConfigPath}, opt.ConfigOverlays...)...)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var faucetCmd = &cli.Command{
+	Name:  CMD_NAME_FAUCET,
+	Usage: "run a faucet HTTP server sending coins from the faucet account of config file",
+	Flags: append(initFlags,
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_LISTEN,
+			Usage: "listen address, eg. 0.0.0.0:4500 (default faucet.host of config file)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_NODE,
+			Usage: "RPC endpoint, eg. tcp://127.0.0.1:26657 (default RPC endpoint of first validator)",
+		},
+	),
+	Before: checkExpect,
+	Action: func(cctx *cli.Context) error {
+		opt := &types.FaucetOption{
+			Option: *newOption(cctx),
+			Listen: cctx.String(CMD_FLAG_NAME_LISTEN),
+			Node:   cctx.String(CMD_FLAG_NAME_NODE),
+		}
+		service := chain.NewFaucet(opt)
+		return service.Run()
+	},
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var genesisCmd = &cli.Command{
+	Name:  CMD_NAME_GENESIS,
+	Usage: "genesis tools of built network",
+	Subcommands: []*cli.Command{
+		genesisMigrateCmd,
+	},
+}
+
+var genesisMigrateCmd = &cli.Command{
+	Name:  CMD_NAME_MIGRATE,
+	Usage: "export state of stopped network and regenerate genesis of a new chain for every validator",
+	Flags: append(initFlags,
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_NEW_NODE_CMD,
+			Usage: "node command of new binary to validate genesis and reset data (default node command)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_NEW_CHAIN_ID,
+			Usage: "chain id of new chain (default genesis chain id of config file)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_INITIAL_HEIGHT,
+			Usage: "initial height of new chain (default exported height + 1)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_GENESIS_TIME,
+			Usage: "genesis time of new chain in RFC3339 format (default now)",
+		},
+		&cli.Int64Flag{
+			Name:  CMD_FLAG_NAME_HEIGHT,
+			Usage: "height to export state at (default latest height)",
+		},
+		&cli.BoolFlag{
+			Name:  CMD_FLAG_NAME_NO_RESET,
+			Usage: "do not reset node data after genesis replaced",
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		opt := &types.GenesisOption{
+			Option:        *newOption(cctx),
+			NewNodeCmd:    cctx.String(CMD_FLAG_NAME_NEW_NODE_CMD),
+			NewChainID:    cctx.String(CMD_FLAG_NAME_NEW_CHAIN_ID),
+			InitialHeight: cctx.String(CMD_FLAG_NAME_INITIAL_HEIGHT),
+			GenesisTime:   cctx.String(CMD_FLAG_NAME_GENESIS_TIME),
+			ExportHeight:  cctx.Int64(CMD_FLAG_NAME_HEIGHT),
+			NoReset:       cctx.Bool(CMD_FLAG_NAME_NO_RESET),
+		}
+		service := chain.NewGenesisMigrator(opt)
+		return service.Run()
+	},
+}
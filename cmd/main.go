@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/civet148/cosmos-cli/chain"
+	_ "github.com/civet148/cosmos-cli/plugins/hobby" //genesis mutators of hobby chain modules, enabled by plugins of config file
 	"github.com/civet148/cosmos-cli/types"
 	"github.com/civet148/cosmos-cli/utils"
 	"github.com/civet148/log"
@@ -22,8 +23,28 @@ var (
 )
 
 const (
-	CMD_NAME_INIT  = "init"
-	CMD_NAME_BUILD = "build"
+	CMD_NAME_INIT      = "init"
+	CMD_NAME_BUILD     = "build"
+	CMD_NAME_VALIDATOR = "validator"
+	CMD_NAME_ADD       = "add"
+	CMD_NAME_NETWORK   = "network"
+	CMD_NAME_CREATE    = "create"
+	CMD_NAME_LIST      = "list"
+	CMD_NAME_SHOW      = "show"
+	CMD_NAME_RM        = "rm"
+	CMD_NAME_SNAPSHOT  = "snapshot"
+	CMD_NAME_RESTORE   = "restore"
+	CMD_NAME_GENESIS   = "genesis"
+	CMD_NAME_MIGRATE   = "migrate"
+	CMD_NAME_UPGRADE   = "upgrade"
+	CMD_NAME_SCHEDULE  = "schedule"
+	CMD_NAME_VERIFY    = "verify"
+	CMD_NAME_CONFIG    = "config"
+	CMD_NAME_RENDER    = "render"
+	CMD_NAME_TX        = "tx"
+	CMD_NAME_FAUCET    = "faucet"
+	CMD_NAME_MONITOR   = "monitor"
+	CMD_NAME_WAIT      = "wait"
 )
 
 const (
@@ -34,6 +55,40 @@ const (
 	CMD_FLAG_NAME_CHAIN_ID        = "chain-id"
 	CMD_FLAG_NAME_KEY_PHRASE      = "key-phrase"
 	CMD_FLAG_NAME_KEYRING_BACKEND = "keyring-backend"
+	CMD_FLAG_NAME_NAME            = "name"
+	CMD_FLAG_NAME_FROM            = "from"
+	CMD_FLAG_NAME_NODE            = "node"
+	CMD_FLAG_NAME_FEES            = "fees"
+	CMD_FLAG_NAME_NETWORK         = "network"
+	CMD_FLAG_NAME_REPORT          = "report"
+	CMD_FLAG_NAME_FORCE           = "force"
+	CMD_FLAG_NAME_ALLOWED_ROOT    = "allowed-root"
+	CMD_FLAG_NAME_BACKUP          = "backup"
+	CMD_FLAG_NAME_BACKUP_DIR      = "backup-dir"
+	CMD_FLAG_NAME_DIR             = "dir"
+	CMD_FLAG_NAME_HOME_ROOT       = "home-root"
+	CMD_FLAG_NAME_HOME_MAP        = "home-map"
+	CMD_FLAG_NAME_NEW_NODE_CMD    = "new-node-cmd"
+	CMD_FLAG_NAME_NEW_CHAIN_ID    = "new-chain-id"
+	CMD_FLAG_NAME_INITIAL_HEIGHT  = "initial-height"
+	CMD_FLAG_NAME_GENESIS_TIME    = "genesis-time"
+	CMD_FLAG_NAME_HEIGHT          = "height"
+	CMD_FLAG_NAME_NO_RESET        = "no-reset"
+	CMD_FLAG_NAME_INFO            = "info"
+	CMD_FLAG_NAME_DEPOSIT         = "deposit"
+	CMD_FLAG_NAME_NO_WATCH        = "no-watch"
+	CMD_FLAG_NAME_TIMEOUT         = "timeout"
+	CMD_FLAG_NAME_FROM_SOURCE     = "from-source"
+	CMD_FLAG_NAME_LDFLAGS         = "ldflags"
+	CMD_FLAG_NAME_TAGS            = "tags"
+	CMD_FLAG_NAME_BUILD_OUTPUT    = "build-output"
+	CMD_FLAG_NAME_GAS_PRICES      = "gas-prices"
+	CMD_FLAG_NAME_LISTEN          = "listen"
+	CMD_FLAG_NAME_INTERVAL        = "interval"
+	CMD_FLAG_NAME_STALL           = "stall"
+	CMD_FLAG_NAME_COUNT           = "count"
+	CMD_FLAG_NAME_EXIT_CODE       = "exit-code"
+	CMD_FLAG_NAME_QUORUM          = "quorum"
 )
 
 func init() {
@@ -66,6 +121,17 @@ func main() {
 
 	local := []*cli.Command{
 		buildCmd,
+		validatorCmd,
+		networkCmd,
+		snapshotCmd,
+		genesisCmd,
+		upgradeCmd,
+		verifyCmd,
+		configCmd,
+		txCmd,
+		faucetCmd,
+		monitorCmd,
+		waitCmd,
 	}
 	app := &cli.App{
 		Name:     ProgramName,
@@ -86,16 +152,15 @@ var initFlags = []cli.Flag{
 		Name:  CMD_FLAG_NAME_DEBUG,
 		Usage: "debug mode on",
 	},
-	&cli.StringFlag{
+	&cli.StringSliceFlag{
 		Name:    CMD_FLAG_NAME_CONFIG,
-		Usage:   "config file path",
-		Value:   types.DEFAULT_CONFIG_FILE,
+		Usage:   "config file path, repeat it to deep merge overlay files in order, eg. -c base.yml -c fast.yml",
+		Value:   cli.NewStringSlice(types.DEFAULT_CONFIG_FILE),
 		Aliases: []string{"c"},
 	},
 	&cli.StringFlag{
 		Name:    CMD_FLAG_NAME_NODE_CMD,
-		Usage:   "node command",
-		Value:   types.DEFAULT_NODE_CMD,
+		Usage:   "node command (default build.binary of config file or " + types.DEFAULT_NODE_CMD + ")",
 		Aliases: []string{"n"},
 	},
 	&cli.StringFlag{
@@ -122,46 +187,132 @@ var initFlags = []cli.Flag{
 		Value:   types.DEFAULT_KEYRING_BACKEND,
 		Aliases: []string{"k"},
 	},
+	&cli.StringFlag{
+		Name:    CMD_FLAG_NAME_NETWORK,
+		Usage:   "named network in workspace (overrides config file path and places validator homes in workspace)",
+		Aliases: []string{"N"},
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_REPORT,
+		Usage: "build report file path (default report path of network if --network specified)",
+	},
 }
 
+var buildFlags = append(initFlags,
+	&cli.StringSliceFlag{
+		Name:  CMD_FLAG_NAME_ALLOWED_ROOT,
+		Usage: "validator homes are only allowed to be deleted inside these root directories (default: homes of network or workspace root)",
+	},
+	&cli.BoolFlag{
+		Name:    CMD_FLAG_NAME_FORCE,
+		Usage:   "delete validator homes containing existing chain data without confirmation",
+		Aliases: []string{"f"},
+	},
+	&cli.BoolFlag{
+		Name:  CMD_FLAG_NAME_BACKUP,
+		Usage: "archive existing validator homes into timestamped tarballs before deleting, keep updated config files as .bak",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_BACKUP_DIR,
+		Usage: "directory of home archives (default parent directory of each home or network backups directory)",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_FROM_SOURCE,
+		Usage: "build node binary from chain app git repository and use it as node command",
+	},
+	&cli.StringSliceFlag{
+		Name:  CMD_FLAG_NAME_LDFLAGS,
+		Usage: "go build ldflags of --from-source (default build.ldflags of config file)",
+	},
+	&cli.StringSliceFlag{
+		Name:  CMD_FLAG_NAME_TAGS,
+		Usage: "go build tags of --from-source (default build.tags of config file)",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_BUILD_OUTPUT,
+		Usage: "binary cache directory of --from-source (default build.output of config file or " + types.DEFAULT_WORKSPACE_DIR + "/" + types.WORKSPACE_BIN_SUBPATH + ")",
+	},
+)
+
 var buildCmd = &cli.Command{
 	Name:      CMD_NAME_BUILD,
 	Usage:     "build cosmos chain nodes",
 	Aliases:   []string{CMD_NAME_INIT},
 	ArgsUsage: "",
-	Flags:     initFlags,
-	Before: func(context *cli.Context) error {
-		//check shells command installed or not before init chain
-		cmd := utils.NewCmdExecutor(false)
-		ok := cmd.Which(types.COMMAND_NAME_EXPECT)
-		if !ok {
-			if cmd.Which(types.COMMAND_NAME_APT_GET) {
-				_, err := cmd.Shell("sudo apt-get install -y expect")
-				if err != nil {
-					return err
-				}
-			} else if cmd.Which(types.COMMAND_NAME_YUM) {
-				_, err := cmd.Shell("sudo yum install -y expect")
-				if err != nil {
-					return err
-				}
-			} else {
-				return fmt.Errorf("%s command not found, please install expect first", types.COMMAND_NAME_EXPECT)
-			}
-		}
-		return nil
-	},
+	Flags:     buildFlags,
+	Before:    checkExpect,
 	Action: func(cctx *cli.Context) error {
-		opt := &types.Option{
-			Debug:          cctx.Bool(CMD_FLAG_NAME_DEBUG),
-			ConfigPath:     cctx.String(CMD_FLAG_NAME_CONFIG),
-			NodeCmd:        cctx.String(CMD_FLAG_NAME_NODE_CMD),
-			DefaultDenom:   cctx.String(CMD_FLAG_NAME_DEFAULT_DENOM),
-			ChainID:        cctx.String(CMD_FLAG_NAME_CHAIN_ID),
-			KeyPhrase:      cctx.String(CMD_FLAG_NAME_KEY_PHRASE),
-			KeyringBackend: cctx.String(CMD_FLAG_NAME_KEYRING_BACKEND),
-		}
+		opt := newOption(cctx)
+		opt.AllowedRoots = cctx.StringSlice(CMD_FLAG_NAME_ALLOWED_ROOT)
+		opt.Force = cctx.Bool(CMD_FLAG_NAME_FORCE)
+		opt.Backup = cctx.Bool(CMD_FLAG_NAME_BACKUP)
+		opt.BackupDir = cctx.String(CMD_FLAG_NAME_BACKUP_DIR)
+		opt.FromSource = cctx.String(CMD_FLAG_NAME_FROM_SOURCE)
+		opt.LDFlags = cctx.StringSlice(CMD_FLAG_NAME_LDFLAGS)
+		opt.BuildTags = cctx.StringSlice(CMD_FLAG_NAME_TAGS)
+		opt.BuildOutput = cctx.String(CMD_FLAG_NAME_BUILD_OUTPUT)
 		service := chain.NewChainBuilder(opt)
 		return service.Run()
 	},
 }
+
+var verifyCmd = &cli.Command{
+	Name:      CMD_NAME_VERIFY,
+	Usage:     "verify validator homes share the identical genesis and persistent peers reference real node ids",
+	ArgsUsage: "",
+	Flags:     initFlags,
+	Action: func(cctx *cli.Context) error {
+		service := chain.NewNetworkVerifier(newOption(cctx))
+		return service.Run()
+	},
+}
+
+func newOption(cctx *cli.Context) *types.Option {
+	var strConfig string
+	var overlays []string
+	if paths := cctx.StringSlice(CMD_FLAG_NAME_CONFIG); len(paths) != 0 {
+		strConfig, overlays = paths[0], paths[1:]
+	}
+	return &types.Option{
+		Debug:          cctx.Bool(CMD_FLAG_NAME_DEBUG),
+		ConfigPath:     strConfig,
+		ConfigOverlays: overlays,
+		NodeCmd:        nodeCmd(cctx),
+		DefaultDenom:   cctx.String(CMD_FLAG_NAME_DEFAULT_DENOM),
+		ChainID:        cctx.String(CMD_FLAG_NAME_CHAIN_ID),
+		KeyPhrase:      cctx.String(CMD_FLAG_NAME_KEY_PHRASE),
+		KeyringBackend: cctx.String(CMD_FLAG_NAME_KEYRING_BACKEND),
+		Network:        cctx.String(CMD_FLAG_NAME_NETWORK),
+		ReportPath:     cctx.String(CMD_FLAG_NAME_REPORT),
+	}
+}
+
+// nodeCmd returns node command flag only if it is set explicitly, otherwise build.binary of config file is preferred
+func nodeCmd(cctx *cli.Context) string {
+	if cctx.IsSet(CMD_FLAG_NAME_NODE_CMD) {
+		return cctx.String(CMD_FLAG_NAME_NODE_CMD)
+	}
+	return ""
+}
+
+// checkExpect checks shells command installed or not before running chain commands
+func checkExpect(cctx *cli.Context) error {
+	cmd := utils.NewCmdExecutor(false)
+	ok := cmd.Which(types.COMMAND_NAME_EXPECT)
+	if !ok {
+		if cmd.Which(types.COMMAND_NAME_APT_GET) {
+			_, err := cmd.Shell("sudo apt-get install -y expect")
+			if err != nil {
+				return err
+			}
+		} else if cmd.Which(types.COMMAND_NAME_YUM) {
+			_, err := cmd.Shell("sudo yum install -y expect")
+			if err != nil {
+				return err
+			}
+		} else {
+			return fmt.Errorf("%s command not found, please install expect first", types.COMMAND_NAME_EXPECT)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var monitorCmd = &cli.Command{
+	Name:  CMD_NAME_MONITOR,
+	Usage: "poll validator RPC endpoints of config file and report block production, peers and missed blocks",
+	Flags: append(initFlags,
+		&cli.DurationFlag{
+			Name:  CMD_FLAG_NAME_INTERVAL,
+			Usage: "interval of polling validator RPC endpoints",
+			Value: types.MONITOR_DEFAULT_INTERVAL * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:  CMD_FLAG_NAME_STALL,
+			Usage: "duration without new block regarded as stalled",
+			Value: types.MONITOR_DEFAULT_STALL * time.Second,
+		},
+		&cli.IntFlag{
+			Name:  CMD_FLAG_NAME_COUNT,
+			Usage: "rounds of polling (forever if zero)",
+		},
+		&cli.BoolFlag{
+			Name:  CMD_FLAG_NAME_EXIT_CODE,
+			Usage: "exit non-zero once a node is unreachable or stalled or the network forked (for CI)",
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		opt := &types.MonitorOption{
+			Option:   *newOption(cctx),
+			Interval: cctx.Duration(CMD_FLAG_NAME_INTERVAL),
+			Stall:    cctx.Duration(CMD_FLAG_NAME_STALL),
+			Count:    cctx.Int(CMD_FLAG_NAME_COUNT),
+			ExitCode: cctx.Bool(CMD_FLAG_NAME_EXIT_CODE),
+		}
+		service := chain.NewNetworkMonitor(opt)
+		return service.Run()
+	},
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/config"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/civet148/cosmos-cli/utils"
+	"github.com/civet148/cosmos-cli/workspace"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+	"os"
+	"text/tabwriter"
+)
+
+var networkCmd = &cli.Command{
+	Name:  CMD_NAME_NETWORK,
+	Usage: "manage named networks in workspace (" + types.DEFAULT_WORKSPACE_DIR + " or $" + types.ENV_NAME_WORKSPACE + ")",
+	Subcommands: []*cli.Command{
+		networkCreateCmd,
+		networkListCmd,
+		networkShowCmd,
+		networkRmCmd,
+	},
+}
+
+var networkCreateCmd = &cli.Command{
+	Name:      CMD_NAME_CREATE,
+	Usage:     "create a named network from config file",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    CMD_FLAG_NAME_CONFIG,
+			Usage:   "config file path",
+			Value:   types.DEFAULT_CONFIG_FILE,
+			Aliases: []string{"c"},
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		n := workspace.New("").Network(cctx.Args().First())
+		if err := n.Create(cctx.String(CMD_FLAG_NAME_CONFIG)); err != nil {
+			return err
+		}
+		fmt.Printf("network %s created at %s\n", n.Name, n.Dir)
+		return nil
+	},
+}
+
+var networkListCmd = &cli.Command{
+	Name:    CMD_NAME_LIST,
+	Usage:   "list named networks",
+	Aliases: []string{"ls"},
+	Action: func(cctx *cli.Context) error {
+		networks, err := workspace.New("").Networks()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCHAIN ID\tVALIDATORS\tLAST STAGE\tSTATUS\tTIME")
+		for _, n := range networks {
+			var strStage, strStatus, strTime string
+			ic, _ := loadNetworkConfig(n)
+			entries, _ := n.ReadJournal()
+			if len(entries) != 0 {
+				last := entries[len(entries)-1]
+				strStage, strStatus, strTime = last.Command+"/"+last.Stage, last.Status, last.Time.Format(types.TIME_FORMAT_DISPLAY)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", n.Name, ic.Genesis.ChainID, len(ic.Validators), strStage, strStatus, strTime)
+		}
+		return w.Flush()
+	},
+}
+
+var networkShowCmd = &cli.Command{
+	Name:      CMD_NAME_SHOW,
+	Usage:     "show named network paths, validators and state journal",
+	ArgsUsage: "<name>",
+	Action: func(cctx *cli.Context) error {
+		n := workspace.New("").Network(cctx.Args().First())
+		if !n.Exists() {
+			return fmt.Errorf("network %s not found", n.Name)
+		}
+		ic, err := loadNetworkConfig(n)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("name:     %s\n", n.Name)
+		fmt.Printf("chain id: %s\n", ic.Genesis.ChainID)
+		fmt.Printf("config:   %s\n", n.ConfigPath())
+		fmt.Printf("homes:    %s\n", n.HomesDir())
+		fmt.Printf("logs:     %s\n", n.LogsDir())
+		fmt.Printf("reports:  %s\n", n.ReportsDir())
+		fmt.Printf("journal:  %s\n", n.JournalPath())
+		fmt.Printf("validators:\n")
+		for _, v := range ic.Validators {
+			fmt.Printf("  %-16s %-16s %s\n", v.Name, v.IP, v.Home)
+		}
+		entries, err := n.ReadJournal()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("journal:\n")
+		for _, e := range entries {
+			fmt.Printf("  %s %-16s %-22s %-6s %s\n", e.Time.Format(types.TIME_FORMAT_DISPLAY), e.Command, e.Stage, e.Status, e.Error)
+		}
+		return nil
+	},
+}
+
+var networkRmCmd = &cli.Command{
+	Name:      CMD_NAME_RM,
+	Usage:     "remove named network including its validator homes",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    CMD_FLAG_NAME_FORCE,
+			Usage:   "remove without confirmation",
+			Aliases: []string{"f"},
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		n := workspace.New("").Network(cctx.Args().First())
+		if !n.Exists() {
+			return fmt.Errorf("network %s not found", n.Name)
+		}
+		if !cctx.Bool(CMD_FLAG_NAME_FORCE) && !utils.Confirm(fmt.Sprintf("remove network %s at %s?", n.Name, n.Dir)) {
+			return fmt.Errorf("network %s not removed, use --force to remove it without confirmation", n.Name)
+		}
+		if err := n.Remove(); err != nil {
+			return err
+		}
+		fmt.Printf("network %s removed\n", n.Name)
+		return nil
+	},
+}
+
+func loadNetworkConfig(n *workspace.Network) (ic *types.IgniteConfig, err error) {
+	ic = &types.IgniteConfig{}
+	data, err := config.Load(n.ConfigPath())
+	if err != nil {
+		return ic, err
+	}
+	return ic, yaml.Unmarshal(data, ic)
+}
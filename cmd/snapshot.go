@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+	"strings"
+)
+
+var snapshotFlags = append(initFlags,
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_DIR,
+		Usage: "snapshots directory (default ./snapshots or snapshots directory of network)",
+	},
+)
+
+var snapshotCmd = &cli.Command{
+	Name:  CMD_NAME_SNAPSHOT,
+	Usage: "snapshot and restore a whole built network",
+	Subcommands: []*cli.Command{
+		snapshotCreateCmd,
+		snapshotRestoreCmd,
+		snapshotListCmd,
+	},
+}
+
+var snapshotCreateCmd = &cli.Command{
+	Name:      CMD_NAME_CREATE,
+	Usage:     "archive validator homes, config file and build report into a checksummed bundle (stop nodes first)",
+	ArgsUsage: "[name]",
+	Flags:     snapshotFlags,
+	Action: func(cctx *cli.Context) error {
+		opt, err := newSnapshotOption(cctx)
+		if err != nil {
+			return err
+		}
+		return chain.NewSnapshotManager(opt).Create()
+	},
+}
+
+var snapshotRestoreCmd = &cli.Command{
+	Name:      CMD_NAME_RESTORE,
+	Usage:     "restore validator homes and config file from a snapshot bundle",
+	ArgsUsage: "<name>",
+	Flags: append(snapshotFlags,
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_HOME_ROOT,
+			Usage: "restore validator homes into this directory by validator name",
+		},
+		&cli.StringSliceFlag{
+			Name:  CMD_FLAG_NAME_HOME_MAP,
+			Usage: "restore validator home to another path, eg. validator1=/data/restored/node1",
+		},
+		&cli.StringSliceFlag{
+			Name:  CMD_FLAG_NAME_ALLOWED_ROOT,
+			Usage: "validator homes are only allowed to be overwritten inside these root directories",
+		},
+		&cli.BoolFlag{
+			Name:    CMD_FLAG_NAME_FORCE,
+			Usage:   "overwrite existing validator homes without confirmation",
+			Aliases: []string{"f"},
+		},
+		&cli.BoolFlag{
+			Name:  CMD_FLAG_NAME_BACKUP,
+			Usage: "archive existing validator homes into timestamped tarballs before overwriting",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_BACKUP_DIR,
+			Usage: "directory of home archives (default parent directory of each home or network backups directory)",
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		opt, err := newSnapshotOption(cctx)
+		if err != nil {
+			return err
+		}
+		if opt.Name == "" {
+			return fmt.Errorf("snapshot name required")
+		}
+		return chain.NewSnapshotManager(opt).Restore()
+	},
+}
+
+var snapshotListCmd = &cli.Command{
+	Name:    CMD_NAME_LIST,
+	Usage:   "list snapshot bundles",
+	Aliases: []string{"ls"},
+	Flags:   snapshotFlags,
+	Action: func(cctx *cli.Context) error {
+		opt, err := newSnapshotOption(cctx)
+		if err != nil {
+			return err
+		}
+		return chain.NewSnapshotManager(opt).List()
+	},
+}
+
+func newSnapshotOption(cctx *cli.Context) (*types.SnapshotOption, error) {
+	opt := &types.SnapshotOption{
+		Option:      *newOption(cctx),
+		Name:        cctx.Args().First(),
+		Dir:         cctx.String(CMD_FLAG_NAME_DIR),
+		RestoreRoot: cctx.String(CMD_FLAG_NAME_HOME_ROOT),
+		HomeMap:     make(map[string]string),
+	}
+	opt.AllowedRoots = cctx.StringSlice(CMD_FLAG_NAME_ALLOWED_ROOT)
+	opt.Force = cctx.Bool(CMD_FLAG_NAME_FORCE)
+	opt.Backup = cctx.Bool(CMD_FLAG_NAME_BACKUP)
+	opt.BackupDir = cctx.String(CMD_FLAG_NAME_BACKUP_DIR)
+	for _, strMap := range cctx.StringSlice(CMD_FLAG_NAME_HOME_MAP) {
+		kv := strings.SplitN(strMap, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid home map %q, expect <validator>=<path>", strMap)
+		}
+		opt.HomeMap[kv[0]] = kv[1]
+	}
+	return opt, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var txFlags = append(initFlags,
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_FROM,
+		Usage: "account name in config file to sign transactions (default first validator)",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_NODE,
+		Usage: "RPC endpoint, eg. tcp://127.0.0.1:26657 (default RPC endpoint of signer validator)",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_FEES,
+		Usage: "fees to pay along with transactions (pay by gas prices if not set)",
+	},
+	&cli.StringFlag{
+		Name:  CMD_FLAG_NAME_GAS_PRICES,
+		Usage: "gas prices, eg. 10000000uhby (default minimum-gas-prices of signer validator)",
+	},
+)
+
+var txCmd = &cli.Command{
+	Name:  CMD_NAME_TX,
+	Usage: "send transactions to built network by account names of config file",
+	Subcommands: []*cli.Command{
+		newTxCmd(types.TX_ACTION_SEND, "<to> <coins>", "send coins to an account name or address"),
+		newTxCmd(types.TX_ACTION_DELEGATE, "<validator> <amount>", "delegate to a validator name or validator address"),
+		newTxCmd(types.TX_ACTION_VOTE, "<proposal-id> <option>", "vote on a proposal (by every validator if --from not set)"),
+		newTxCmd(types.TX_ACTION_SUBMIT_PROPOSAL, "<proposal.json>", "submit a governance proposal of JSON file"),
+	},
+}
+
+func newTxCmd(strAction, strArgsUsage, strUsage string) *cli.Command {
+	return &cli.Command{
+		Name:      strAction,
+		Usage:     strUsage,
+		ArgsUsage: strArgsUsage,
+		Flags:     txFlags,
+		Before:    checkExpect,
+		Action: func(cctx *cli.Context) error {
+			opt := &types.TxOption{
+				Option:    *newOption(cctx),
+				Action:    strAction,
+				Args:      cctx.Args().Slice(),
+				From:      cctx.String(CMD_FLAG_NAME_FROM),
+				Node:      cctx.String(CMD_FLAG_NAME_NODE),
+				Fees:      cctx.String(CMD_FLAG_NAME_FEES),
+				GasPrices: cctx.String(CMD_FLAG_NAME_GAS_PRICES),
+			}
+			service := chain.NewTxSender(opt)
+			return service.Run()
+		},
+	}
+}
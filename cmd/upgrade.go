@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var upgradeCmd = &cli.Command{
+	Name:  CMD_NAME_UPGRADE,
+	Usage: "software upgrade rehearsal on built network",
+	Subcommands: []*cli.Command{
+		upgradeScheduleCmd,
+	},
+}
+
+var upgradeScheduleCmd = &cli.Command{
+	Name:  CMD_NAME_SCHEDULE,
+	Usage: "submit software upgrade proposal, vote yes by every validator and watch heights until the upgrade",
+	Flags: append(initFlags,
+		&cli.StringFlag{
+			Name:     CMD_FLAG_NAME_NAME,
+			Usage:    "upgrade plan name (cosmovisor/upgrades/<name>)",
+			Required: true,
+		},
+		&cli.Int64Flag{
+			Name:     CMD_FLAG_NAME_HEIGHT,
+			Usage:    "upgrade height",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_INFO,
+			Usage: "upgrade plan info",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_DEPOSIT,
+			Usage: "proposal deposit (default gov min_deposit of config file)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_FROM,
+			Usage: "proposer account name (default first validator)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_NODE,
+			Usage: "RPC endpoint, eg. tcp://127.0.0.1:26657 (default RPC endpoint of first validator)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_FEES,
+			Usage: "fees to pay along with transactions",
+		},
+		&cli.BoolFlag{
+			Name:  CMD_FLAG_NAME_NO_WATCH,
+			Usage: "do not watch heights until the upgrade height",
+		},
+		&cli.DurationFlag{
+			Name:  CMD_FLAG_NAME_TIMEOUT,
+			Usage: "timeout of watching heights, eg. 30m (no timeout if zero)",
+		},
+	),
+	Before: checkExpect,
+	Action: func(cctx *cli.Context) error {
+		opt := &types.UpgradeOption{
+			Option:  *newOption(cctx),
+			Name:    cctx.String(CMD_FLAG_NAME_NAME),
+			Height:  cctx.Int64(CMD_FLAG_NAME_HEIGHT),
+			Info:    cctx.String(CMD_FLAG_NAME_INFO),
+			Deposit: cctx.String(CMD_FLAG_NAME_DEPOSIT),
+			From:    cctx.String(CMD_FLAG_NAME_FROM),
+			Node:    cctx.String(CMD_FLAG_NAME_NODE),
+			Fees:    cctx.String(CMD_FLAG_NAME_FEES),
+			NoWatch: cctx.Bool(CMD_FLAG_NAME_NO_WATCH),
+			Timeout: cctx.Duration(CMD_FLAG_NAME_TIMEOUT),
+		}
+		service := chain.NewUpgradeScheduler(opt)
+		return service.Run()
+	},
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var validatorCmd = &cli.Command{
+	Name:  CMD_NAME_VALIDATOR,
+	Usage: "manage validators of an existing network",
+	Subcommands: []*cli.Command{
+		validatorAddCmd,
+	},
+}
+
+var validatorAddCmd = &cli.Command{
+	Name:      CMD_NAME_ADD,
+	Usage:     "join a new validator declared in config file to an existing network",
+	ArgsUsage: "",
+	Flags: append(initFlags,
+		&cli.StringFlag{
+			Name:     CMD_FLAG_NAME_NAME,
+			Usage:    "validator name in config file",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_FROM,
+			Usage: "account name to fund the new validator (default first validator)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_NODE,
+			Usage: "RPC endpoint to broadcast transactions, eg. tcp://127.0.0.1:26657 (generate unsigned transactions only if not set)",
+		},
+		&cli.StringFlag{
+			Name:  CMD_FLAG_NAME_FEES,
+			Usage: "fees to pay along with transactions, eg. 10000000000000000usby",
+		},
+	),
+	Before: checkExpect,
+	Action: func(cctx *cli.Context) error {
+		opt := &types.ValidatorOption{
+			Option: *newOption(cctx),
+			Name:   cctx.String(CMD_FLAG_NAME_NAME),
+			From:   cctx.String(CMD_FLAG_NAME_FROM),
+			Node:   cctx.String(CMD_FLAG_NAME_NODE),
+			Fees:   cctx.String(CMD_FLAG_NAME_FEES),
+		}
+		service := chain.NewValidatorAdder(opt)
+		return service.Run()
+	},
+}
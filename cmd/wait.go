@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	"github.com/civet148/cosmos-cli/chain"
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/urfave/cli/v2"
+)
+
+var waitCmd = &cli.Command{
+	Name:  CMD_NAME_WAIT,
+	Usage: "wait until validators of config file reach a height and are not catching up (for CI)",
+	Flags: append(initFlags,
+		&cli.Int64Flag{
+			Name:  CMD_FLAG_NAME_HEIGHT,
+			Usage: "target height",
+			Value: 1,
+		},
+		&cli.IntFlag{
+			Name:  CMD_FLAG_NAME_QUORUM,
+			Usage: "count of validators to reach target height (all validators if zero)",
+		},
+		&cli.DurationFlag{
+			Name:  CMD_FLAG_NAME_INTERVAL,
+			Usage: "interval of polling validator RPC endpoints",
+			Value: types.WAIT_DEFAULT_INTERVAL * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:  CMD_FLAG_NAME_TIMEOUT,
+			Usage: "timeout of waiting, eg. 2m",
+			Value: types.WAIT_DEFAULT_TIMEOUT * time.Second,
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		opt := &types.WaitOption{
+			Option:   *newOption(cctx),
+			Height:   cctx.Int64(CMD_FLAG_NAME_HEIGHT),
+			Quorum:   cctx.Int(CMD_FLAG_NAME_QUORUM),
+			Interval: cctx.Duration(CMD_FLAG_NAME_INTERVAL),
+			Timeout:  cctx.Duration(CMD_FLAG_NAME_TIMEOUT),
+		}
+		service := chain.NewNetworkWaiter(opt)
+		return service.Run()
+	},
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+const keyName = "name"
+
+// Load renders config files, deep merges them in order (later file wins) and expands validator template
+// of the merged config. see Merge for how values are merged.
+func Load(paths ...string) ([]byte, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config file")
+	}
+	var data []byte
+	var merged yaml.MapSlice
+	for _, strPath := range paths {
+		rendered, err := os.ReadFile(strPath)
+		if err != nil {
+			return nil, err
+		}
+		if rendered, err = Render(rendered); err != nil {
+			return nil, fmt.Errorf("render config file %s error: %s", strPath, err)
+		}
+		if len(paths) == 1 {
+			//a single file is kept as it is
+			data = rendered
+			break
+		}
+		var doc yaml.MapSlice
+		if err = yaml.Unmarshal(rendered, &doc); err != nil {
+			return nil, fmt.Errorf("decode config file %s error: %s", strPath, err)
+		}
+		merged = Merge(merged, doc)
+	}
+	var err error
+	if data == nil {
+		if data, err = yaml.Marshal(merged); err != nil {
+			return nil, err
+		}
+	}
+	if data, err = ExpandValidatorTemplate(data); err != nil {
+		return nil, fmt.Errorf("expand config file %s error: %s", paths[0], err)
+	}
+	return data, nil
+}
+
+// Merge deep merges overlay into base and returns the result, key order of base is kept and new keys
+// of overlay are appended.
+//
+//   - maps are merged key by key, a null value of overlay removes the key
+//   - lists of maps which all have a name (validators, accounts) are merged by name: items of the same
+//     name are deep merged, new items are appended
+//   - any other list and scalar of overlay replaces the base value
+func Merge(base, overlay yaml.MapSlice) yaml.MapSlice {
+	var out = make(yaml.MapSlice, 0, len(base)+len(overlay))
+	out = append(out, base...)
+	for _, item := range overlay {
+		i := indexOf(out, fmt.Sprintf("%v", item.Key))
+		switch {
+		case item.Value == nil && i >= 0:
+			out = append(out[:i], out[i+1:]...)
+		case item.Value == nil:
+		case i < 0:
+			out = append(out, item)
+		default:
+			out[i].Value = mergeValue(out[i].Value, item.Value)
+		}
+	}
+	return out
+}
+
+func mergeValue(base, overlay interface{}) interface{} {
+	switch ov := overlay.(type) {
+	case yaml.MapSlice:
+		if bv, ok := base.(yaml.MapSlice); ok {
+			return Merge(bv, ov)
+		}
+	case []interface{}:
+		if bv, ok := base.([]interface{}); ok && isNamedList(bv) && isNamedList(ov) {
+			return mergeNamedList(bv, ov)
+		}
+	}
+	return overlay
+}
+
+// isNamedList reports whether all items of list are maps with a name
+func isNamedList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		ms, ok := item.(yaml.MapSlice)
+		if !ok || valueOf(ms, keyName) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeNamedList(base, overlay []interface{}) []interface{} {
+	var out = make([]interface{}, 0, len(base)+len(overlay))
+	out = append(out, base...)
+	for _, item := range overlay {
+		ms := item.(yaml.MapSlice)
+		strName := fmt.Sprintf("%v", valueOf(ms, keyName))
+		var merged bool
+		for i, b := range out {
+			bms := b.(yaml.MapSlice)
+			if fmt.Sprintf("%v", valueOf(bms, keyName)) == strName {
+				out[i], merged = Merge(bms, ms), true
+				break
+			}
+		}
+		if !merged {
+			out = append(out, ms)
+		}
+	}
+	return out
+}
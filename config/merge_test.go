@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testBaseConfig = `version: 1
+accounts:
+- name: validator1
+  coins: ["1000uhby"]
+- name: validator2
+  coins: ["1000uhby"]
+validators:
+- name: validator1
+  bonded: 100uhby
+  home: /data/node1
+  ip: 127.0.0.1
+  config:
+    moniker: node1
+    consensus:
+      timeout_commit: "5s"
+- name: validator2
+  bonded: 100uhby
+  home: /data/node2
+  ip: 127.0.0.1
+  config:
+    moniker: node2
+    consensus:
+      timeout_commit: "5s"
+client:
+  openapi:
+    path: docs/static/openapi.yml
+genesis:
+  chain_id: "test_9000-1"
+  app_state:
+    mint:
+      params:
+        blocks_per_year: "6311520"
+    gov:
+      params:
+        min_deposit:
+        - amount: "10000"
+          denom: uhby
+        - amount: "10000"
+          denom: usby
+`
+
+const testOverlayConfig = `validators:
+- name: validator2
+  config:
+    consensus:
+      timeout_commit: "1s"
+- name: validator3
+  bonded: 100uhby
+  home: /data/node3
+  ip: 127.0.0.1
+accounts:
+- name: validator3
+  coins: ["1000uhby"]
+client: null
+genesis:
+  app_state:
+    gov:
+      params:
+        min_deposit:
+        - amount: "1"
+          denom: uhby
+`
+
+func TestLoadOverlays(t *testing.T) {
+	root := t.TempDir()
+	strBase, strOverlay := filepath.Join(root, "base.yml"), filepath.Join(root, "fast.yml")
+	require.NoError(t, os.WriteFile(strBase, []byte(testBaseConfig), 0o644))
+	require.NoError(t, os.WriteFile(strOverlay, []byte(testOverlayConfig), 0o644))
+
+	data, err := Load(strBase, strOverlay)
+	require.NoError(t, err)
+	var ic types.IgniteConfig
+	require.NoError(t, yaml.Unmarshal(data, &ic))
+
+	//named lists merged by name
+	require.Len(t, ic.Validators, 3)
+	require.Equal(t, "5s", ic.Validators[0].Config.Consensus.TimeoutCommit)
+	require.Equal(t, "1s", ic.Validators[1].Config.Consensus.TimeoutCommit)
+	require.Equal(t, "node2", ic.Validators[1].Config.Moniker)
+	require.Equal(t, "/data/node2", ic.Validators[1].Home)
+	require.Equal(t, "validator3", ic.Validators[2].Name)
+	require.Len(t, ic.Accounts, 3)
+
+	var doc map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+	//null removes key
+	require.NotContains(t, doc, "client")
+	//other lists replaced, sibling keys kept
+	genesis := doc["genesis"].(map[interface{}]interface{})
+	appState := genesis["app_state"].(map[interface{}]interface{})
+	gov := appState["gov"].(map[interface{}]interface{})["params"].(map[interface{}]interface{})
+	require.Len(t, gov["min_deposit"], 1)
+	require.Contains(t, appState, "mint")
+	require.Equal(t, "test_9000-1", genesis["chain_id"])
+
+	//a single file is returned as rendered
+	data, err = Load(strBase)
+	require.NoError(t, err)
+	require.Equal(t, testBaseConfig, string(data))
+}
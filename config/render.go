@@ -0,0 +1,154 @@
+// Package config renders config file of cosmos-cli before it is decoded into types.IgniteConfig.
+//
+// A config file is rendered in two passes:
+//
+//  1. ${NAME} and ${NAME:-default} are replaced by environment variables, $${NAME} escapes a literal ${NAME},
+//     YAML comments are left as is
+//  2. the result is executed as Go template, so that one template is able to describe an N-validator network,
+//     eg. {{ range $i := seq 4 }} ... name: validator{{ add $i 1 }} ... ip: {{ ipAdd "172.20.0.101" $i }} {{ end }}
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var envRegexp = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Render expands environment variables and executes Go template of config content
+func Render(data []byte) ([]byte, error) {
+	strText, err := ExpandEnv(string(data))
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.New("config").Option("missingkey=error").Funcs(Funcs()).Parse(strText)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = tpl.Execute(&buf, map[string]interface{}{"Env": environ()}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExpandEnv replaces ${NAME} and ${NAME:-default} by environment variables, an undefined variable
+// without default value is an error. comments are skipped so that they may mention variables freely.
+func ExpandEnv(strText string) (string, error) {
+	var undefined []string
+	expand := func(s string) string {
+		if strings.HasPrefix(s, "$$") {
+			return s[1:]
+		}
+		sub := envRegexp.FindStringSubmatch(s)
+		if v, ok := os.LookupEnv(sub[1]); ok {
+			return v
+		}
+		if sub[2] != "" {
+			return sub[3]
+		}
+		undefined = append(undefined, sub[1])
+		return s
+	}
+	lines := strings.Split(strText, "\n")
+	for i, strLine := range lines {
+		n := commentIndex(strLine)
+		lines[i] = envRegexp.ReplaceAllStringFunc(strLine[:n], expand) + strLine[n:]
+	}
+	if len(undefined) != 0 {
+		return "", fmt.Errorf("undefined environment variable %s", strings.Join(undefined, ","))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// commentIndex returns index of YAML comment of line, length of line if no comment. # starts a comment at line
+// start or after a space or tab outside quoted strings, a quote opens a string at the start of a value only
+func commentIndex(strLine string) int {
+	var quote byte
+	for i := 0; i < len(strLine); i++ {
+		c := strLine[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" \t:[{,-", strLine[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || strLine[i-1] == ' ' || strLine[i-1] == '\t' {
+				return i
+			}
+		}
+	}
+	return len(strLine)
+}
+
+func environ() map[string]string {
+	var env = make(map[string]string)
+	for _, kv := range os.Environ() {
+		if idx := strings.IndexByte(kv, '='); idx > 0 {
+			env[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	return env
+}
+
+// Funcs returns functions available in config template
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"seq":      seq,
+		"add":      func(a, b int) int { return a + b },
+		"sub":      func(a, b int) int { return a - b },
+		"mul":      func(a, b int) int { return a * b },
+		"ipAdd":    ipAdd,
+		"cidrHost": cidrHost,
+		"env": func(strName string, defaults ...string) string {
+			if v, ok := os.LookupEnv(strName); ok || len(defaults) == 0 {
+				return v
+			}
+			return defaults[0]
+		},
+	}
+}
+
+// seq returns 0..n-1
+func seq(n int) []int {
+	var s = make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// ipAdd returns IPv4 address of ip plus n, eg. ipAdd "172.20.0.101" 2 => 172.20.0.103
+func ipAdd(strIP string, n int) (string, error) {
+	ip := net.ParseIP(strIP).To4()
+	if ip == nil {
+		return "", fmt.Errorf("invalid IPv4 address %s", strIP)
+	}
+	v := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+	v += uint32(n)
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).String(), nil
+}
+
+// cidrHost returns the nth host address of IPv4 network, eg. cidrHost "172.20.0.0/24" 101 => 172.20.0.101
+func cidrHost(strCIDR string, n int) (string, error) {
+	ip, ipNet, err := net.ParseCIDR(strCIDR)
+	if err != nil || ip.To4() == nil {
+		return "", fmt.Errorf("invalid IPv4 CIDR %s", strCIDR)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if n <= 0 || uint64(n) >= uint64(1)<<uint(bits-ones)-1 {
+		return "", fmt.Errorf("host %d out of CIDR %s", n, strCIDR)
+	}
+	return ipAdd(ipNet.IP.String(), n)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("COSMOS_CLI_TEST_HOME", "/data")
+	s, err := ExpandEnv(`home: "${COSMOS_CLI_TEST_HOME}/node1" denom: ${COSMOS_CLI_TEST_DENOM:-uhby} raw: $${COSMOS_CLI_TEST_HOME}`)
+	require.NoError(t, err)
+	require.Equal(t, `home: "/data/node1" denom: uhby raw: ${COSMOS_CLI_TEST_HOME}`, s)
+
+	_, err = ExpandEnv(`home: ${COSMOS_CLI_TEST_UNDEFINED}`)
+	require.Error(t, err)
+
+	//variables in comments are left as is, # in quoted strings or words does not start a comment
+	s, err = ExpandEnv("# home: ${COSMOS_CLI_TEST_UNDEFINED}\n" +
+		"home: ${COSMOS_CLI_TEST_HOME} # or ${COSMOS_CLI_TEST_UNDEFINED}\n" +
+		"memo: \"#${COSMOS_CLI_TEST_HOME} # \\\" ${COSMOS_CLI_TEST_HOME}\" # ${COSMOS_CLI_TEST_UNDEFINED}\n" +
+		"moniker: bob's#${COSMOS_CLI_TEST_HOME} # ${COSMOS_CLI_TEST_UNDEFINED}\n")
+	require.NoError(t, err)
+	require.Equal(t, "# home: ${COSMOS_CLI_TEST_UNDEFINED}\n"+
+		"home: /data # or ${COSMOS_CLI_TEST_UNDEFINED}\n"+
+		"memo: \"#/data # \\\" /data\" # ${COSMOS_CLI_TEST_UNDEFINED}\n"+
+		"moniker: bob's#/data # ${COSMOS_CLI_TEST_UNDEFINED}\n", s)
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("COSMOS_CLI_TEST_COUNT", "3")
+	const tpl = `validators:
+{{- range $i := seq ${COSMOS_CLI_TEST_COUNT} }}
+- name: validator{{ add $i 1 }}
+  home: /data/node{{ add $i 1 }}
+  ip: {{ cidrHost "172.20.0.0/24" (add 101 $i) }}
+  config:
+    rpc:
+      laddr: tcp://0.0.0.0:{{ add 26657 (mul $i 10000) }}
+{{- end }}
+`
+	data, err := Render([]byte(tpl))
+	require.NoError(t, err)
+	require.Equal(t, `validators:
+- name: validator1
+  home: /data/node1
+  ip: 172.20.0.101
+  config:
+    rpc:
+      laddr: tcp://0.0.0.0:26657
+- name: validator2
+  home: /data/node2
+  ip: 172.20.0.102
+  config:
+    rpc:
+      laddr: tcp://0.0.0.0:36657
+- name: validator3
+  home: /data/node3
+  ip: 172.20.0.103
+  config:
+    rpc:
+      laddr: tcp://0.0.0.0:46657
+`, string(data))
+
+	ip, err := ipAdd("10.0.0.255", 1)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.1.0", ip)
+	_, err = cidrHost("10.0.0.0/30", 3)
+	require.Error(t, err)
+	_, err = Render([]byte(`{{ .Undefined }}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	keyValidatorTemplate = "validator_template"
+	keyValidators        = "validators"
+	keyAccounts          = "accounts"
+	indexPlaceholder     = "%d"
+)
+
+// ValidatorTemplate expands into count validators and their accounts. %d of name, home and moniker is
+// replaced by validator index starting from 1, the index is appended if there is no %d.
+//
+//	validator_template:
+//	  count: 50
+//	  name: validator           # validator1..validator50 (default)
+//	  home: /data/node%d
+//	  moniker: node             # node1..node50 (default)
+//	  ip_cidr: 172.20.0.0/24    # or a fixed ip if count is 1, listeners of one host would collide
+//	  ip_start: 101             # host number of first validator in CIDR (default 1)
+//	  coins: ["400000000000000000000000uhby"]
+//	  bonded: 200000000000000000000000uhby
+//	  app: {...}                # copied to every validator
+//	  config: {...}             # copied to every validator
+type ValidatorTemplate struct {
+	Count   int           `yaml:"count"`
+	Name    string        `yaml:"name"`
+	Home    string        `yaml:"home"`
+	Moniker string        `yaml:"moniker"`
+	IP      string        `yaml:"ip"`
+	IPCIDR  string        `yaml:"ip_cidr"`
+	IPStart int           `yaml:"ip_start"`
+	Coins   []string      `yaml:"coins"`
+	Bonded  string        `yaml:"bonded"`
+	App     yaml.MapSlice `yaml:"app"`
+	Config  yaml.MapSlice `yaml:"config"`
+}
+
+// ExpandValidatorTemplate replaces validator_template of config content by generated validators and
+// accounts appended after the ones written explicitly. content without template is returned as is.
+func ExpandValidatorTemplate(data []byte) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	idx := indexOf(doc, keyValidatorTemplate)
+	if idx < 0 {
+		return data, nil
+	}
+	var vt ValidatorTemplate
+	if err := remarshal(doc[idx].Value, &vt); err != nil {
+		return nil, fmt.Errorf("%s: %s", keyValidatorTemplate, err)
+	}
+	if err := vt.check(); err != nil {
+		return nil, fmt.Errorf("%s: %s", keyValidatorTemplate, err)
+	}
+	doc = append(doc[:idx], doc[idx+1:]...)
+
+	validators, _ := valueOf(doc, keyValidators).([]interface{})
+	accounts, _ := valueOf(doc, keyAccounts).([]interface{})
+	var names = make(map[string]bool)
+	for _, v := range validators {
+		if ms, ok := v.(yaml.MapSlice); ok {
+			names[fmt.Sprintf("%v", valueOf(ms, "name"))] = true
+		}
+	}
+	var accountNames = make(map[string]bool)
+	for _, a := range accounts {
+		if ms, ok := a.(yaml.MapSlice); ok {
+			accountNames[fmt.Sprintf("%v", valueOf(ms, "name"))] = true
+		}
+	}
+	for i := 1; i <= vt.Count; i++ {
+		v, err := vt.validator(i)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", keyValidatorTemplate, err)
+		}
+		strName := fmt.Sprintf("%v", valueOf(v, "name"))
+		if names[strName] {
+			return nil, fmt.Errorf("%s: validator %s already exists", keyValidatorTemplate, strName)
+		}
+		names[strName] = true
+		validators = append(validators, v)
+		if !accountNames[strName] {
+			accounts = append(accounts, yaml.MapSlice{
+				{Key: "name", Value: strName},
+				{Key: "coins", Value: toInterfaces(vt.Coins)},
+			})
+		}
+	}
+	doc = setValue(doc, keyAccounts, accounts)
+	doc = setValue(doc, keyValidators, validators)
+	return yaml.Marshal(doc)
+}
+
+func (vt *ValidatorTemplate) check() error {
+	if vt.Count <= 0 {
+		return fmt.Errorf("count must be greater than 0")
+	}
+	if vt.Home == "" {
+		return fmt.Errorf("home is empty")
+	}
+	if vt.IP == "" && vt.IPCIDR == "" {
+		return fmt.Errorf("ip or ip_cidr is required")
+	}
+	if vt.IPCIDR == "" && vt.Count > 1 {
+		return fmt.Errorf("fixed ip %s with count %d makes every validator listen on the same ports, use ip_cidr instead", vt.IP, vt.Count)
+	}
+	if len(vt.Coins) == 0 {
+		return fmt.Errorf("coins is empty")
+	}
+	if vt.Bonded == "" {
+		return fmt.Errorf("bonded is empty")
+	}
+	if vt.Name == "" {
+		vt.Name = "validator"
+	}
+	if vt.Moniker == "" {
+		vt.Moniker = "node"
+	}
+	if vt.IPStart == 0 {
+		vt.IPStart = 1
+	}
+	return nil
+}
+
+// validator returns validator i (starting from 1) of template
+func (vt *ValidatorTemplate) validator(i int) (v yaml.MapSlice, err error) {
+	strIP := vt.IP
+	if vt.IPCIDR != "" {
+		if strIP, err = cidrHost(vt.IPCIDR, vt.IPStart+i-1); err != nil {
+			return nil, err
+		}
+	}
+	var conf yaml.MapSlice
+	if err = remarshal(vt.Config, &conf); err != nil {
+		return nil, err
+	}
+	conf = setValue(conf, "moniker", formatIndex(vt.Moniker, i))
+	v = yaml.MapSlice{
+		{Key: "name", Value: formatIndex(vt.Name, i)},
+		{Key: "bonded", Value: vt.Bonded},
+		{Key: "home", Value: formatIndex(vt.Home, i)},
+		{Key: "ip", Value: strIP},
+	}
+	if len(vt.App) != 0 {
+		var app yaml.MapSlice
+		if err = remarshal(vt.App, &app); err != nil {
+			return nil, err
+		}
+		v = append(v, yaml.MapItem{Key: "app", Value: app})
+	}
+	return append(v, yaml.MapItem{Key: "config", Value: conf}), nil
+}
+
+func formatIndex(s string, i int) string {
+	if strings.Contains(s, indexPlaceholder) {
+		return strings.ReplaceAll(s, indexPlaceholder, strconv.Itoa(i))
+	}
+	return s + strconv.Itoa(i)
+}
+
+// remarshal converts in to out through YAML, it also deep copies MapSlice
+func remarshal(in, out interface{}) error {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+func indexOf(ms yaml.MapSlice, strKey string) int {
+	for i, item := range ms {
+		if fmt.Sprintf("%v", item.Key) == strKey {
+			return i
+		}
+	}
+	return -1
+}
+
+func valueOf(ms yaml.MapSlice, strKey string) interface{} {
+	if i := indexOf(ms, strKey); i >= 0 {
+		return ms[i].Value
+	}
+	return nil
+}
+
+// setValue replaces value of key or appends it if key not exist
+func setValue(ms yaml.MapSlice, strKey string, v interface{}) yaml.MapSlice {
+	if i := indexOf(ms, strKey); i >= 0 {
+		ms[i].Value = v
+		return ms
+	}
+	return append(ms, yaml.MapItem{Key: strKey, Value: v})
+}
+
+func toInterfaces(ss []string) []interface{} {
+	var out []interface{}
+	for _, s := range ss {
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testTemplateConfig = `version: 1
+accounts:
+- name: faucet
+  coins: ["1000uhby"]
+validators:
+- name: seed
+  bonded: 100uhby
+  home: /data/seed
+  ip: 172.20.0.100
+  config:
+    moniker: seed
+validator_template:
+  count: 3
+  home: /data/node%d
+  ip_cidr: 172.20.0.0/24
+  ip_start: 101
+  coins: ["400000000000000000000000uhby"]
+  bonded: 200000000000000000000000uhby
+  config:
+    consensus:
+      timeout_commit: "1s"
+    p2p:
+      laddr: "tcp://0.0.0.0:26656"
+genesis:
+  chain_id: "test_9000-1"
+`
+
+func TestExpandValidatorTemplate(t *testing.T) {
+	data, err := ExpandValidatorTemplate([]byte(testTemplateConfig))
+	require.NoError(t, err)
+
+	var ic types.IgniteConfig
+	require.NoError(t, yaml.Unmarshal(data, &ic))
+	require.Len(t, ic.Validators, 4)
+	require.Len(t, ic.Accounts, 4)
+	require.Equal(t, "seed", ic.Validators[0].Name)
+	for i := 1; i <= 3; i++ {
+		v := ic.Validators[i]
+		require.Equal(t, fmt.Sprintf("validator%d", i), v.Name)
+		require.Equal(t, fmt.Sprintf("/data/node%d", i), v.Home)
+		require.Equal(t, fmt.Sprintf("node%d", i), v.Config.Moniker)
+		require.Equal(t, fmt.Sprintf("172.20.0.%d", 100+i), v.IP)
+		require.Equal(t, "200000000000000000000000uhby", v.Bonded)
+		require.Equal(t, "1s", v.Config.Consensus.TimeoutCommit)
+		require.Equal(t, v.Name, ic.Accounts[i].Name)
+		require.Equal(t, []string{"400000000000000000000000uhby"}, ic.Accounts[i].Coins)
+	}
+	require.Equal(t, "test_9000-1", ic.Genesis.ChainID)
+	require.NotContains(t, string(data), "validator_template")
+
+	//config without template is untouched
+	data, err = ExpandValidatorTemplate([]byte("version: 1\n"))
+	require.NoError(t, err)
+	require.Equal(t, "version: 1\n", string(data))
+
+	_, err = ExpandValidatorTemplate([]byte("validator_template:\n  count: 2\n  name: seed\n  home: /data\n  ip_cidr: 172.20.0.0/24\n  coins: [1uhby]\n  bonded: 1uhby\nvalidators:\n- name: seed1\n"))
+	require.ErrorContains(t, err, "already exists")
+	_, err = ExpandValidatorTemplate([]byte("validator_template:\n  count: 2\n"))
+	require.Error(t, err)
+
+	//a fixed ip is only allowed for a single validator, more would collide on the same listeners
+	_, err = ExpandValidatorTemplate([]byte("validator_template:\n  count: 2\n  home: /data/node%d\n  ip: 127.0.0.1\n  coins: [1uhby]\n  bonded: 1uhby\n"))
+	require.ErrorContains(t, err, "same ports")
+	data, err = ExpandValidatorTemplate([]byte("validator_template:\n  count: 1\n  home: /data/node%d\n  ip: 127.0.0.1\n  coins: [1uhby]\n  bonded: 1uhby\n"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "ip: 127.0.0.1")
+}
@@ -0,0 +1,39 @@
+package confile
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalJSONIndent is indentation of canonical JSON, the same as genesis written by cosmos-sdk
+const CanonicalJSONIndent = "  "
+
+// MarshalCanonicalJSON encodes v as deterministic JSON compatible with genesis written by cosmos-sdk:
+// object keys sorted, two spaces indentation and a trailing newline. numbers are kept as their literal
+// text (json.Number) so big integers like 400000000000000000000000 never lose precision.
+func MarshalCanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	//decode again so that struct fields are sorted as well
+	var canonical interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&canonical); err != nil {
+		return nil, err
+	}
+	if data, err = json.MarshalIndent(canonical, "", CanonicalJSONIndent); err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// SaveCanonicalJSON saves v into config file as canonical JSON and returns the bytes written
+func (c *ConfigFile) SaveCanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := MarshalCanonicalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return data, c.WriteFile(data)
+}
@@ -0,0 +1,52 @@
+package confile
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	const content = `{"chain_id":"hobby","app_state":{"bank":{"supply":[{"denom":"usby","amount":"400000000000000000000000"}],"limit":400000000000000000000000}},"initial_height":"1"}`
+	strPath := writeTestFile(t, "genesis.json", content)
+
+	var genesis map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&genesis))
+
+	data, err := NewFile(strPath).SaveCanonicalJSON(genesis)
+	require.NoError(t, err)
+	expected := `{
+  "app_state": {
+    "bank": {
+      "limit": 400000000000000000000000,
+      "supply": [
+        {
+          "amount": "400000000000000000000000",
+          "denom": "usby"
+        }
+      ]
+    }
+  },
+  "chain_id": "hobby",
+  "initial_height": "1"
+}
+`
+	require.Equal(t, expected, string(data))
+	saved, err := os.ReadFile(strPath)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(saved))
+
+	//deterministic for struct input as well
+	type doc struct {
+		Z string      `json:"z"`
+		A json.Number `json:"a"`
+	}
+	data, err = MarshalCanonicalJSON(doc{Z: "z", A: "12345678901234567890123"})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"a\": 12345678901234567890123,\n  \"z\": \"z\"\n}\n", string(data))
+}
@@ -11,10 +11,15 @@ import (
 type ConfigFile struct {
 	creator EncodingCreator
 	path    string
+	backup  bool
 }
 
+// BackupSuffix is appended to config file path to keep the previous content when backup enabled
+const BackupSuffix = ".bak"
+
 // New starts a new ConfigFile by using creator as underlying EncodingCreator to encode and
-// decode config file that presents or will present on path.
+// decode config file that presents or will present on path. if creator is nil the format is
+// detected from file extension or content, see DetectFormat.
 func New(creator EncodingCreator, path string) *ConfigFile {
 	return &ConfigFile{
 		creator: creator,
@@ -22,6 +27,12 @@ func New(creator EncodingCreator, path string) *ConfigFile {
 	}
 }
 
+// WithBackup keeps the previous content of config file as <path>.bak on every write
+func (c *ConfigFile) WithBackup() *ConfigFile {
+	c.backup = true
+	return c
+}
+
 // Load loads content of config file into v if file exist on path.
 // otherwise nothing loaded into v and no error is returned.
 func (c *ConfigFile) Load(v interface{}) error {
@@ -33,41 +44,106 @@ func (c *ConfigFile) Load(v interface{}) error {
 		return err
 	}
 	defer file.Close()
-	return c.creator.Create(file).Decode(v)
+	return c.encodingCreator().Create(file).Decode(v)
 }
 
-// SaveJSON saves v into file as JSON format
+// SaveJSON saves v into file as JSON format, indentation of existing file is kept (tab by default)
 func (c *ConfigFile) SaveJSON(v interface{}) error {
-	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
-		return err
+	strIndent := "\t"
+	if data, err := os.ReadFile(c.path); err == nil {
+		if s := DetectIndent(data); s != "" {
+			strIndent = s
+		}
 	}
-	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	data, err := json.MarshalIndent(v, "", strIndent)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	var data []byte
-	data, err = json.MarshalIndent(v, "", "\t")
-	if err != nil {
+	return c.WriteFile(data)
+}
+
+// Save saves v into config file by overwriting the previous content it also creates the
+// config file if it wasn't exist. indentation of existing file is kept if the encoding supports it.
+func (c *ConfigFile) Save(v interface{}) error {
+	creator := c.encodingCreator()
+	var strIndent string
+	if data, err := os.ReadFile(c.path); err == nil {
+		strIndent = DetectIndent(data)
+	}
+	return c.writeAtomic(func(file *os.File) error {
+		if ic, ok := creator.(IndentEncodingCreator); ok && strIndent != "" {
+			return ic.CreateIndent(file, strIndent).Encode(v)
+		}
+		return creator.Create(file).Encode(v)
+	})
+}
+
+// WriteFile overwrites config file with data
+func (c *ConfigFile) WriteFile(data []byte) error {
+	return c.writeAtomic(func(file *os.File) error {
+		_, err := file.Write(data)
+		return err
+	})
+}
+
+// writeAtomic writes a temp file in the same directory of config file, syncs and renames it over config
+// file so a crash or write error never leaves a truncated config file. mode of existing file is preserved
+// and the previous content is kept as <path>.bak if backup enabled.
+func (c *ConfigFile) writeAtomic(write func(file *os.File) error) (err error) {
+	strDir := filepath.Dir(c.path)
+	if err = os.MkdirAll(strDir, 0o755); err != nil {
 		return err
 	}
-	_, err = file.Write(data)
-	if err != nil {
+	var mode os.FileMode = 0o644
+	fi, err := os.Stat(c.path)
+	if err == nil {
+		mode = fi.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	var exists = err == nil
+	var file *os.File
+	if file, err = os.CreateTemp(strDir, "."+filepath.Base(c.path)+".tmp-*"); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+	if err = write(file); err != nil {
+		return err
+	}
+	if err = file.Chmod(mode); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
 		return err
 	}
+	if c.backup && exists {
+		if err = copyFile(c.path, c.path+BackupSuffix, mode); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(file.Name(), c.path); err != nil {
+		return err
+	}
+	//persist the rename, not all platforms support syncing a directory
+	if dir, e := os.Open(strDir); e == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
 	return nil
 }
 
-// Save saves v into config file by overwriting the previous content it also creates the
-// config file if it wasn't exist.
-func (c *ConfigFile) Save(v interface{}) error {
-	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
-		return err
-	}
-	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+func copyFile(strSrc, strDst string, mode os.FileMode) error {
+	data, err := os.ReadFile(strSrc)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	return c.creator.Create(file).Encode(v)
+	return os.WriteFile(strDst, data, mode)
 }
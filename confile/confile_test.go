@@ -1,8 +1,10 @@
 package confile
 
 import (
+	"errors"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -51,3 +53,45 @@ func TestAll(t *testing.T) {
 		})
 	}
 }
+
+type failEncodingCreator struct{}
+
+func (e *failEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
+	return NewEncoding(&failEncoder{w: rw}, nil)
+}
+
+// failEncoder writes partial content and then fails
+type failEncoder struct {
+	w io.Writer
+}
+
+func (e *failEncoder) Encode(v interface{}) error {
+	_, _ = e.w.Write([]byte(`{"hello":`))
+	return errors.New("encode failed")
+}
+
+func TestSaveAtomic(t *testing.T) {
+	strDir := t.TempDir()
+	strPath := filepath.Join(strDir, "genesis.json")
+	const original = `{"hello":"world"}`
+	require.NoError(t, os.WriteFile(strPath, []byte(original), 0o600))
+
+	require.Error(t, New(&failEncodingCreator{}, strPath).WithBackup().Save(map[string]string{"hello": "cosmos"}))
+	data, err := os.ReadFile(strPath)
+	require.NoError(t, err)
+	require.Equal(t, original, string(data))
+	entries, err := os.ReadDir(strDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temp file left behind")
+
+	require.NoError(t, New(DefaultJSONEncodingCreator, strPath).WithBackup().SaveJSON(map[string]string{"hello": "cosmos"}))
+	data, err = os.ReadFile(strPath + BackupSuffix)
+	require.NoError(t, err)
+	require.Equal(t, original, string(data))
+	fi, err := os.Stat(strPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	var d map[string]string
+	require.NoError(t, New(DefaultJSONEncodingCreator, strPath).Load(&d))
+	require.Equal(t, "cosmos", d["hello"])
+}
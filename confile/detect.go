@@ -0,0 +1,135 @@
+package confile
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Format is encoding format of config file
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJSONC Format = "jsonc" // JSON with comments, trailing commas and JSON5 style keys/strings
+	FormatYAML  Format = "yaml"
+	FormatTOML  Format = "toml"
+	FormatEnv   Format = "env"
+)
+
+var (
+	envLineRegexp   = regexp.MustCompile(`^(export\s+)?[A-Za-z_][A-Za-z0-9_]*=`)
+	tomlLineRegexp  = regexp.MustCompile(`^([A-Za-z0-9_."'-]+\s*=|\[[A-Za-z0-9_."' -]+\]$)`)
+	yamlLineRegexp  = regexp.MustCompile(`^(-\s|[^\s:#]+:(\s|$))`)
+	jsoncMarkRegexp = regexp.MustCompile(`(^|\s)(//|/\*)|,\s*[}\]]`)
+)
+
+// Creator returns EncodingCreator of format, nil if format unknown
+func (f Format) Creator() EncodingCreator {
+	switch f {
+	case FormatJSON:
+		return DefaultJSONEncodingCreator
+	case FormatJSONC:
+		return DefaultJSONCEncodingCreator
+	case FormatYAML:
+		return DefaultYAMLEncodingCreator
+	case FormatTOML:
+		return DefaultTOMLEncodingCreator
+	case FormatEnv:
+		return DefaultEnvEncodingCreator
+	}
+	return nil
+}
+
+// NewFile starts a new ConfigFile which detects encoding format from file extension or file content
+func NewFile(path string) *ConfigFile {
+	return New(nil, path)
+}
+
+// DetectFormat detects format of config file by extension first and then by sniffing content.
+// it falls back to JSON if nothing can be told.
+func DetectFormat(path string, data []byte) Format {
+	if f := formatOfExt(path); f != "" {
+		return f
+	}
+	if f := sniffFormat(data); f != "" {
+		return f
+	}
+	return FormatJSON
+}
+
+func formatOfExt(path string) Format {
+	strBase := strings.ToLower(filepath.Base(path))
+	if strBase == ".env" || strings.HasPrefix(strBase, ".env.") {
+		return FormatEnv
+	}
+	switch filepath.Ext(strBase) {
+	case ".json":
+		return FormatJSON
+	case ".jsonc", ".json5":
+		return FormatJSONC
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".env":
+		return FormatEnv
+	}
+	return ""
+}
+
+// sniffFormat tells format by the first significant line of content
+func sniffFormat(data []byte) Format {
+	strText := strings.TrimSpace(string(data))
+	if strings.HasPrefix(strText, "{") || strings.HasPrefix(strText, "//") || strings.HasPrefix(strText, "/*") {
+		if jsoncMarkRegexp.MatchString(strText) {
+			return FormatJSONC
+		}
+		return FormatJSON
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		strLine := strings.TrimSpace(scanner.Text())
+		if strLine == "" || strings.HasPrefix(strLine, "#") || strLine == "---" {
+			continue
+		}
+		switch {
+		case envLineRegexp.MatchString(strLine):
+			return FormatEnv
+		case tomlLineRegexp.MatchString(strLine):
+			return FormatTOML
+		case yamlLineRegexp.MatchString(strLine):
+			return FormatYAML
+		case strings.HasPrefix(strLine, "["):
+			return FormatJSON
+		}
+		return ""
+	}
+	return ""
+}
+
+// encodingCreator returns creator of config file, detects it from path and content if not given
+func (c *ConfigFile) encodingCreator() EncodingCreator {
+	if c.creator != nil {
+		return c.creator
+	}
+	data, _ := os.ReadFile(c.path)
+	return DetectFormat(c.path, data).Creator()
+}
+
+// DetectIndent returns indentation of the first indented line of data, empty if no line indented
+func DetectIndent(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		strLine := scanner.Text()
+		strTrimmed := strings.TrimLeft(strLine, " \t")
+		if strTrimmed == "" || strTrimmed == strLine {
+			continue
+		}
+		return strLine[:len(strLine)-len(strTrimmed)]
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package confile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		name    string
+		path    string
+		content string
+		format  Format
+	}{
+		{"json ext", "genesis.json", ``, FormatJSON},
+		{"jsonc ext", "patch.jsonc", ``, FormatJSONC},
+		{"json5 ext", "patch.json5", ``, FormatJSONC},
+		{"yaml ext", "config.yml", ``, FormatYAML},
+		{"toml ext", "app.toml", ``, FormatTOML},
+		{"env file", ".env", ``, FormatEnv},
+		{"env ext", "node.env", ``, FormatEnv},
+		{"sniff json", "patch", `{"a": 1}`, FormatJSON},
+		{"sniff jsonc", "patch", "{\n  // comment\n  \"a\": 1,\n}", FormatJSONC},
+		{"sniff toml", "config", "# comment\n[p2p]\nladdr = \"\"", FormatTOML},
+		{"sniff toml key", "config", "proxy_app = \"tcp://127.0.0.1:26658\"", FormatTOML},
+		{"sniff yaml", "config", "---\ngenesis:\n  chain_id: hobby", FormatYAML},
+		{"sniff env", "config", "# comment\nexport CHAIN_ID=hobby", FormatEnv},
+		{"fallback", "config", ``, FormatJSON},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.format, DetectFormat(tt.path, []byte(tt.content)))
+		})
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	content := `{
+  // line comment
+  chain_id: 'hobby\'s "net"', /* block
+  comment */
+  "url": "http://a//b/*c*/",
+  "list": [1, 2, 3,],
+  max_supply: 100000000000000000000000001,
+}`
+	var v map[string]interface{}
+	require.NoError(t, NewFile(writeTestFile(t, "patch.jsonc", content)).Load(&v))
+	require.Equal(t, `hobby's "net"`, v["chain_id"])
+	require.Equal(t, "http://a//b/*c*/", v["url"])
+	require.Equal(t, []interface{}{json.Number("1"), json.Number("2"), json.Number("3")}, v["list"])
+	require.Equal(t, json.Number("100000000000000000000000001"), v["max_supply"])
+}
+
+func TestEnvEncoding(t *testing.T) {
+	strPath := writeTestFile(t, "node.env", "# chain\nexport CHAIN_ID=hobby # inline\nKEY_PHRASE=\"a b\\tc\"\nDENOM='usby'\nEMPTY=\n")
+	var v map[string]string
+	cf := NewFile(strPath)
+	require.NoError(t, cf.Load(&v))
+	require.Equal(t, map[string]string{"CHAIN_ID": "hobby", "KEY_PHRASE": "a b\tc", "DENOM": "usby", "EMPTY": ""}, v)
+
+	require.NoError(t, cf.Save(map[string]interface{}{"CHAIN_ID": "hobby", "KEY_PHRASE": "a b", "HEIGHT": 1000000}))
+	data, err := os.ReadFile(strPath)
+	require.NoError(t, err)
+	require.Equal(t, "CHAIN_ID=hobby\nHEIGHT=1000000\nKEY_PHRASE=\"a b\"\n", string(data))
+}
+
+func TestSaveKeepsIndent(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		content  string
+		expected string
+	}{
+		{"json two spaces", "genesis.json", "{\n  \"a\": 1\n}\n", "{\n  \"a\": 2\n}\n"},
+		{"json tab", "genesis.json", "{\n\t\"a\": 1\n}\n", "{\n\t\"a\": 2\n}\n"},
+		{"yaml four spaces", "config.yml", "b:\n    a: 1\n", "b:\n    a: 2\n"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			strPath := writeTestFile(t, tt.path, tt.content)
+			var v map[string]interface{}
+			cf := NewFile(strPath)
+			require.NoError(t, cf.Load(&v))
+			if b, ok := v["b"].(map[string]interface{}); ok {
+				b["a"] = 2
+			} else {
+				v["a"] = 2
+			}
+			require.NoError(t, cf.Save(v))
+			data, err := os.ReadFile(strPath)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, strName, strContent string) string {
+	strPath := filepath.Join(t.TempDir(), strName)
+	require.NoError(t, os.WriteFile(strPath, []byte(strContent), 0o644))
+	return strPath
+}
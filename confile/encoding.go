@@ -3,6 +3,7 @@ package confile
 import (
 	"encoding/json"
 	"io"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/pelletier/go-toml"
@@ -20,6 +21,13 @@ type EncodeDecoder interface {
 	Decoder
 }
 
+// IndentEncodingCreator is an EncodingCreator which is able to encode with given indentation
+// so that saving a config file keeps its original indentation style.
+type IndentEncodingCreator interface {
+	EncodingCreator
+	CreateIndent(rw io.ReadWriter, indent string) EncodeDecoder
+}
+
 // Encoder should encode a v into io.Writer given to EncodingCreator.
 type Encoder interface {
 	Encode(v interface{}) error
@@ -53,6 +61,12 @@ var DefaultYAMLEncodingCreator = &YAMLEncodingCreator{}
 // DefaultTOMLEncodingCreator implements EncodingCreator for TOML encoding.
 var DefaultTOMLEncodingCreator = &TOMLEncodingCreator{}
 
+// DefaultJSONCEncodingCreator implements EncodingCreator for JSONC/JSON5 encoding.
+var DefaultJSONCEncodingCreator = &JSONCEncodingCreator{}
+
+// DefaultEnvEncodingCreator implements EncodingCreator for .env encoding.
+var DefaultEnvEncodingCreator = &EnvEncodingCreator{}
+
 // JSONEncodingCreator implements EncodingCreator for JSON encoding.
 type JSONEncodingCreator struct{}
 
@@ -60,16 +74,53 @@ func (e *JSONEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
 	return NewEncoding(json.NewEncoder(rw), json.NewDecoder(rw))
 }
 
-// YAMLEncodingCreator implements EncodingCreator for JSON encoding.
+func (e *JSONEncodingCreator) CreateIndent(rw io.ReadWriter, indent string) EncodeDecoder {
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", indent)
+	return NewEncoding(enc, json.NewDecoder(rw))
+}
+
+// YAMLEncodingCreator implements EncodingCreator for YAML encoding.
 type YAMLEncodingCreator struct{}
 
 func (e *YAMLEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
 	return NewEncoding(yaml.NewEncoder(rw), yaml.NewDecoder(rw))
 }
 
-// TOMLEncodingCreator implements EncodingCreator for JSON encoding.
+func (e *YAMLEncodingCreator) CreateIndent(rw io.ReadWriter, indent string) EncodeDecoder {
+	return NewEncoding(yaml.NewEncoder(rw, yaml.Indent(len(strings.ReplaceAll(indent, "\t", "  ")))), yaml.NewDecoder(rw))
+}
+
+// TOMLEncodingCreator implements EncodingCreator for TOML encoding.
 type TOMLEncodingCreator struct{}
 
 func (e *TOMLEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
 	return NewEncoding(toml.NewEncoder(rw), toml.NewDecoder(rw))
 }
+
+func (e *TOMLEncodingCreator) CreateIndent(rw io.ReadWriter, indent string) EncodeDecoder {
+	return NewEncoding(toml.NewEncoder(rw).Indentation(indent), toml.NewDecoder(rw))
+}
+
+// JSONCEncodingCreator implements EncodingCreator for JSON with comments. decoding accepts // and /* */
+// comments, trailing commas, single quoted strings and unquoted keys (the JSON5 subset used by config
+// patches), encoding writes plain JSON.
+type JSONCEncodingCreator struct{}
+
+func (e *JSONCEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
+	return NewEncoding(json.NewEncoder(rw), &jsoncDecoder{r: rw})
+}
+
+func (e *JSONCEncodingCreator) CreateIndent(rw io.ReadWriter, indent string) EncodeDecoder {
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", indent)
+	return NewEncoding(enc, &jsoncDecoder{r: rw})
+}
+
+// EnvEncodingCreator implements EncodingCreator for .env files of KEY=value lines. values are decoded
+// into v through JSON so maps and structs with json tags are both supported.
+type EnvEncodingCreator struct{}
+
+func (e *EnvEncodingCreator) Create(rw io.ReadWriter) EncodeDecoder {
+	return NewEncoding(&envEncoder{w: rw}, &envDecoder{r: rw})
+}
@@ -0,0 +1,109 @@
+package confile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type envDecoder struct {
+	r io.Reader
+}
+
+func (d *envDecoder) Decode(v interface{}) error {
+	var values = make(map[string]string)
+	scanner := bufio.NewScanner(d.r)
+	for nLine := 1; scanner.Scan(); nLine++ {
+		strLine := strings.TrimSpace(scanner.Text())
+		if strLine == "" || strings.HasPrefix(strLine, "#") {
+			continue
+		}
+		strLine = strings.TrimPrefix(strLine, "export ")
+		idx := strings.IndexByte(strLine, '=')
+		if idx <= 0 {
+			return fmt.Errorf("line %d: expect KEY=value", nLine)
+		}
+		strKey := strings.TrimSpace(strLine[:idx])
+		strValue, err := parseEnvValue(strings.TrimSpace(strLine[idx+1:]))
+		if err != nil {
+			return fmt.Errorf("line %d: %s", nLine, err)
+		}
+		values[strKey] = strValue
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// parseEnvValue unquotes double quoted (with escapes) and single quoted (literal) values, strips
+// trailing comment of unquoted values
+func parseEnvValue(strValue string) (string, error) {
+	switch {
+	case strings.HasPrefix(strValue, `"`):
+		end := strings.LastIndexByte(strValue, '"')
+		if end <= 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strconv.Unquote(strValue[:end+1])
+	case strings.HasPrefix(strValue, `'`):
+		end := strings.LastIndexByte(strValue, '\'')
+		if end <= 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strValue[1:end], nil
+	}
+	if idx := strings.Index(strValue, " #"); idx >= 0 {
+		strValue = strValue[:idx]
+	}
+	return strings.TrimSpace(strValue), nil
+}
+
+type envEncoder struct {
+	w io.Writer
+}
+
+// Encode writes v as sorted KEY=value lines, v is converted through JSON and must be an object of scalars
+func (e *envEncoder) Encode(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var values map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&values); err != nil {
+		return fmt.Errorf("env encoding requires an object: %s", err)
+	}
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		var strValue string
+		switch val := values[k].(type) {
+		case nil:
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("env key %s: nested value is not supported", k)
+		default:
+			strValue = fmt.Sprintf("%v", val)
+		}
+		if strings.ContainsAny(strValue, " \t\n\"'#\\$") {
+			strValue = strconv.Quote(strValue)
+		}
+		sb.WriteString(k + "=" + strValue + "\n")
+	}
+	_, err = io.WriteString(e.w, sb.String())
+	return err
+}
@@ -0,0 +1,160 @@
+package confile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsoncDecoder struct {
+	r io.Reader
+}
+
+func (d *jsoncDecoder) Decode(v interface{}) error {
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	if data, err = StripJSONC(data); err != nil {
+		return err
+	}
+	//keep big integers of genesis (eg. amounts) exact
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// StripJSONC translates JSONC/JSON5 content to standard JSON by removing comments and trailing commas,
+// double quoting single quoted strings and unquoted keys.
+func StripJSONC(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '"':
+			end, err := jsoncStringEnd(data, i)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(data[i:end])
+			i = end
+		case c == '\'':
+			end, err := jsoncStringEnd(data, i)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(requoteJSONCString(data[i+1 : end-1]))
+			i = end
+		case c == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, err := jsoncCommentEnd(data, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == ',':
+			next, err := skipJSONCSpaces(data, i+1)
+			if err != nil {
+				return nil, err
+			}
+			if next >= len(data) || (data[next] != '}' && data[next] != ']') {
+				out.WriteByte(c)
+			}
+			i++
+		case isJSONCIdentStart(c):
+			end := i + 1
+			for end < len(data) && (isJSONCIdentStart(data[end]) || (data[end] >= '0' && data[end] <= '9')) {
+				end++
+			}
+			next, err := skipJSONCSpaces(data, end)
+			if err != nil {
+				return nil, err
+			}
+			if next < len(data) && data[next] == ':' {
+				out.WriteString(`"` + string(data[i:end]) + `"`)
+			} else {
+				out.Write(data[i:end])
+			}
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.Bytes(), nil
+}
+
+func isJSONCIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// jsoncStringEnd returns offset after closing quote of string starting at i
+func jsoncStringEnd(data []byte, i int) (int, error) {
+	quote := data[i]
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", i)
+}
+
+// jsoncCommentEnd returns offset after comment starting at i
+func jsoncCommentEnd(data []byte, i int) (int, error) {
+	if data[i+1] == '/' {
+		end := bytes.IndexByte(data[i:], '\n')
+		if end < 0 {
+			return len(data), nil
+		}
+		return i + end, nil
+	}
+	end := bytes.Index(data[i+2:], []byte("*/"))
+	if end < 0 {
+		return 0, fmt.Errorf("unterminated comment at offset %d", i)
+	}
+	return i + 2 + end + 2, nil
+}
+
+// skipJSONCSpaces returns offset of next significant byte skipping spaces and comments
+func skipJSONCSpaces(data []byte, i int) (int, error) {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n':
+			i++
+		case data[i] == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*'):
+			end, err := jsoncCommentEnd(data, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// requoteJSONCString converts body of a single quoted string to a double quoted JSON string
+func requoteJSONCString(body []byte) string {
+	var out bytes.Buffer
+	out.WriteByte('"')
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			out.WriteByte('\'')
+			i++
+		case body[i] == '\\' && i+1 < len(body):
+			out.Write(body[i : i+2])
+			i++
+		case body[i] == '"':
+			out.WriteString(`\"`)
+		default:
+			out.WriteByte(body[i])
+		}
+	}
+	out.WriteByte('"')
+	return out.String()
+}
@@ -0,0 +1,200 @@
+package confile
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CheckSchema checks nested values to be written into a TOML file against schema, a struct (or pointer to
+// struct) whose toml tags name the keys of the file. values of another scalar type are coerced in place when
+// it is lossless, eg. "100" for an int key or 100 for a string key, other mismatches are errors. keys missing
+// in schema are returned as sorted dotted paths, maps and interface{} fields of schema accept any key.
+func CheckSchema(schema interface{}, values map[string]interface{}) (unknown []string, err error) {
+	t := reflect.TypeOf(schema)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema %s is not a struct", t)
+	}
+	if err = checkTable(t, nil, values, &unknown); err != nil {
+		return nil, err
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// ApplyDefaults sets fields of defaults, a struct (or pointer to struct) whose toml tags name the keys of the file,
+// into values for keys missing in values. nested structs are applied to tables of the same key and a missing table
+// is created. zero fields are skipped so keys without a default keep the value written by init of the node binary.
+func ApplyDefaults(defaults interface{}, values map[string]interface{}) {
+	rv := reflect.Indirect(reflect.ValueOf(defaults))
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), rv.Field(i)
+		strTag := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if f.Anonymous && strTag == "" && f.Type.Kind() == reflect.Struct {
+			ApplyDefaults(fv.Interface(), values)
+			continue
+		}
+		if f.PkgPath != "" || strTag == "" || strTag == "-" || fv.IsZero() {
+			continue
+		}
+		v, ok := values[strTag]
+		if f.Type.Kind() == reflect.Struct {
+			table, isTable := toStringMap(v)
+			if ok && !isTable {
+				continue
+			}
+			if !ok {
+				table = make(map[string]interface{})
+			}
+			ApplyDefaults(fv.Interface(), table)
+			values[strTag] = table
+			continue
+		}
+		if !ok {
+			values[strTag] = fv.Interface()
+		}
+	}
+}
+
+func checkTable(t reflect.Type, prefix []string, values map[string]interface{}, unknown *[]string) (err error) {
+	var fields = make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		strTag := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if f.Anonymous && strTag == "" && f.Type.Kind() == reflect.Struct {
+			//keys of embedded struct are keys of the table
+			for j := 0; j < f.Type.NumField(); j++ {
+				if ef := f.Type.Field(j); ef.Tag.Get("toml") != "" {
+					fields[strings.Split(ef.Tag.Get("toml"), ",")[0]] = ef
+				}
+			}
+			continue
+		}
+		if strTag == "" || strTag == "-" {
+			continue
+		}
+		fields[strTag] = f
+	}
+	for strKey, v := range values {
+		path := append(append([]string{}, prefix...), strKey)
+		f, ok := fields[strKey]
+		if !ok {
+			*unknown = append(*unknown, strings.Join(path, "."))
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		if values[strKey], err = checkValue(f.Type, path, v, unknown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkValue(t reflect.Type, path []string, v interface{}, unknown *[]string) (interface{}, error) {
+	strPath := strings.Join(path, ".")
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map:
+		return v, nil
+	case reflect.Struct:
+		table, ok := toStringMap(v)
+		if !ok {
+			return nil, fmt.Errorf("key %s must be a table but %v", strPath, v)
+		}
+		return table, checkTable(t, path, table, unknown)
+	case reflect.Slice, reflect.Array:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("key %s must be an array but %v", strPath, v)
+		}
+		var items = make([]interface{}, rv.Len())
+		for i := range items {
+			item, err := checkValue(t.Elem(), path, rv.Index(i).Interface(), unknown)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	c, ok := coerceScalar(t.Kind(), v)
+	if !ok {
+		return nil, fmt.Errorf("key %s value %v (%T) is not %s", strPath, v, v, t.Kind())
+	}
+	return c, nil
+}
+
+// coerceScalar returns v as a value of kind if it is lossless
+func coerceScalar(kind reflect.Kind, v interface{}) (interface{}, bool) {
+	if n, ok := v.(json.Number); ok {
+		v = n.String()
+	}
+	switch kind {
+	case reflect.String:
+		switch val := v.(type) {
+		case string:
+			return val, true
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return fmt.Sprintf("%v", val), true
+		}
+	case reflect.Bool:
+		switch val := v.(type) {
+		case bool:
+			return val, true
+		case string:
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b, true
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch val := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return val, true
+		case float64:
+			if val == math.Trunc(val) {
+				return int64(val), true
+			}
+		case string:
+			if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+				return n, true
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch val := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return val, true
+		case string:
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return val, true
+	case map[interface{}]interface{}:
+		var m = make(map[string]interface{}, len(val))
+		for k, mv := range val {
+			m[fmt.Sprintf("%v", k)] = mv
+		}
+		return m, true
+	}
+	return nil, false
+}
@@ -0,0 +1,81 @@
+package confile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSchema struct {
+	Moniker string `toml:"moniker"`
+	RPC     struct {
+		MaxBodyBytes int      `toml:"max_body_bytes"`
+		Origins      []string `toml:"cors_allowed_origins"`
+	} `toml:"rpc"`
+	API struct {
+		Enable            bool `toml:"enable"`
+		EnabledUnsafeCors bool `toml:"enabled-unsafe-cors"`
+	} `toml:"api"`
+	Extra map[string]interface{} `toml:"extra"`
+}
+
+func TestCheckSchema(t *testing.T) {
+	values := map[string]interface{}{
+		"moniker": 1,
+		"rpc": map[interface{}]interface{}{
+			"max_body_bytes":       "10240000",
+			"cors_allowed_origins": []interface{}{"*", 8080},
+		},
+		"api": map[string]interface{}{
+			"enable":             "true",
+			"enable-unsafe-cors": true,
+		},
+		"extra":   map[string]interface{}{"anything": 1},
+		"unknown": "x",
+	}
+	unknown, err := CheckSchema(&testSchema{}, values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.enable-unsafe-cors", "unknown"}, unknown)
+	require.Equal(t, "1", values["moniker"])
+	rpc := values["rpc"].(map[string]interface{})
+	require.Equal(t, int64(10240000), rpc["max_body_bytes"])
+	require.Equal(t, []interface{}{"*", "8080"}, rpc["cors_allowed_origins"])
+	require.Equal(t, true, values["api"].(map[string]interface{})["enable"])
+
+	var cases = []struct {
+		values map[string]interface{}
+		err    string
+	}{
+		{map[string]interface{}{"rpc": map[string]interface{}{"max_body_bytes": "10MB"}}, "key rpc.max_body_bytes value 10MB (string) is not int"},
+		{map[string]interface{}{"rpc": map[string]interface{}{"max_body_bytes": 1.5}}, "key rpc.max_body_bytes value 1.5 (float64) is not int"},
+		{map[string]interface{}{"api": map[string]interface{}{"enable": "yes"}}, "key api.enable value yes (string) is not bool"},
+		{map[string]interface{}{"rpc": "tcp://0.0.0.0:26657"}, "key rpc must be a table but tcp://0.0.0.0:26657"},
+		{map[string]interface{}{"rpc": map[string]interface{}{"cors_allowed_origins": "*"}}, "key rpc.cors_allowed_origins must be an array but *"},
+	}
+	for _, c := range cases {
+		_, err = CheckSchema(testSchema{}, c.values)
+		require.EqualError(t, err, c.err)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	var defaults testSchema
+	defaults.Moniker = "node"
+	defaults.RPC.MaxBodyBytes = 1000
+	defaults.API.Enable = true
+	values := map[string]interface{}{
+		"moniker": "node1",
+		"rpc":     map[string]interface{}{"cors_allowed_origins": []interface{}{"*"}},
+		"api":     nil,
+	}
+	ApplyDefaults(&defaults, values)
+	require.Equal(t, "node1", values["moniker"])
+	require.Equal(t, map[string]interface{}{"cors_allowed_origins": []interface{}{"*"}, "max_body_bytes": 1000}, values["rpc"])
+	//null removes key and zero fields have no default
+	require.Nil(t, values["api"])
+	require.NotContains(t, values, "extra")
+
+	values = map[string]interface{}{}
+	ApplyDefaults(defaults, values)
+	require.Equal(t, map[string]interface{}{"enable": true}, values["api"])
+}
@@ -0,0 +1,397 @@
+package confile
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+var bareKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TOMLDocument edits a TOML document in place. only the values of keys being set are rewritten,
+// comments, key ordering and formatting of the rest of the document are preserved byte-for-byte.
+type TOMLDocument struct {
+	text    string
+	entries map[string]*tomlEntry // key entries by dotted path
+	tables  map[string]*tomlTable // tables by dotted name, root table is ""
+	dotted  map[string][]string   // path of table defining each table implicitly defined by dotted keys
+	arrays  map[string]bool       // names of array of tables
+}
+
+type tomlEntry struct {
+	start int // value start offset
+	end   int // value end offset (exclusive, trailing spaces and comment excluded)
+}
+
+type tomlTable struct {
+	insert  int  // offset where new keys of table are inserted (end of line of last key or header)
+	hasKeys bool // table has keys already
+}
+
+// NewTOMLDocument parses data as TOML document for editing
+func NewTOMLDocument(data []byte) *TOMLDocument {
+	d := &TOMLDocument{}
+	d.parse(string(data))
+	return d
+}
+
+// Bytes returns content of document
+func (d *TOMLDocument) Bytes() []byte {
+	return []byte(d.text)
+}
+
+// Set sets value of key path, keys are case-sensitive. an existing key keeps its position and trailing comment
+// and is left untouched if it holds an equal value already, a missing key is appended to its table and a missing
+// table is appended to the end of document. a missing key of table defined by dotted keys (eg. a.b = 1) is
+// appended as dotted key to the table defining it, a [table] header would redefine the table.
+func (d *TOMLDocument) Set(path []string, v interface{}) (changed bool, err error) {
+	if len(path) == 0 {
+		return false, fmt.Errorf("empty key path")
+	}
+	var strValue string
+	if strValue, err = formatTOMLValue(v); err != nil {
+		return false, fmt.Errorf("key %s: %s", strings.Join(path, "."), err)
+	}
+	strKey := strings.Join(path, ".")
+	if e, ok := d.entries[strKey]; ok {
+		if equalTOMLValue(d.text[e.start:e.end], strValue) {
+			return false, nil
+		}
+		d.parse(d.text[:e.start] + strValue + d.text[e.end:])
+		return true, nil
+	}
+	strLine := formatTOMLKey(path[len(path)-1:]) + " = " + strValue
+	strTable := strings.Join(path[:len(path)-1], ".")
+	if d.arrays[strTable] {
+		return false, fmt.Errorf("key %s: array of tables is not editable", strings.Join(path, "."))
+	}
+	if parent, ok := d.dotted[strTable]; ok {
+		t := d.tables[strings.Join(parent, ".")]
+		strLine = formatTOMLKey(path[len(parent):]) + " = " + strValue
+		d.parse(d.text[:t.insert] + "\n" + strLine + d.text[t.insert:])
+		return true, nil
+	}
+	if t, ok := d.tables[strTable]; ok {
+		if strTable == "" && !t.hasKeys {
+			d.parse(strLine + "\n" + d.text)
+		} else {
+			d.parse(d.text[:t.insert] + "\n" + strLine + d.text[t.insert:])
+		}
+		return true, nil
+	}
+	strText := d.text
+	if strText != "" && !strings.HasSuffix(strText, "\n") {
+		strText += "\n"
+	}
+	strText += "\n[" + formatTOMLKey(path[:len(path)-1]) + "]\n" + strLine + "\n"
+	d.parse(strText)
+	return true, nil
+}
+
+// SetMap sets all leaf values of nested map, maps are treated as tables. keys are set in sorted order so the
+// result is deterministic.
+func (d *TOMLDocument) SetMap(values map[string]interface{}) (changed bool, err error) {
+	return d.setMap(nil, values)
+}
+
+func (d *TOMLDocument) setMap(prefix []string, values map[string]interface{}) (changed bool, err error) {
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := append(append([]string{}, prefix...), k)
+		var ok bool
+		switch val := values[k].(type) {
+		case map[string]interface{}:
+			ok, err = d.setMap(path, val)
+		case map[interface{}]interface{}:
+			var m = make(map[string]interface{}, len(val))
+			for mk, mv := range val {
+				m[fmt.Sprintf("%v", mk)] = mv
+			}
+			ok, err = d.setMap(path, m)
+		case nil:
+			continue
+		default:
+			ok, err = d.Set(path, val)
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || ok
+	}
+	return changed, nil
+}
+
+// EditTOML sets values of nested map into TOML config file in place, see TOMLDocument for what is preserved.
+// the file is rewritten only if any value changed, a missing file is created.
+func (c *ConfigFile) EditTOML(values map[string]interface{}) error {
+	data, err := os.ReadFile(c.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	doc := NewTOMLDocument(data)
+	var changed bool
+	if changed, err = doc.SetMap(values); err != nil || !changed {
+		return err
+	}
+	return c.WriteFile(doc.Bytes())
+}
+
+func (d *TOMLDocument) parse(strText string) {
+	d.text = strText
+	d.entries = make(map[string]*tomlEntry)
+	d.tables = map[string]*tomlTable{"": {}}
+	d.dotted = make(map[string][]string)
+	d.arrays = make(map[string]bool)
+	var strSection string
+	var section []string
+	var arrayTable bool
+	for pos := 0; pos < len(strText); {
+		lineEnd := strings.IndexByte(strText[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(strText)
+		} else {
+			lineEnd += pos
+		}
+		strLine := strings.TrimSpace(strText[pos:lineEnd])
+		switch {
+		case strLine == "" || strings.HasPrefix(strLine, "#"):
+		case strings.HasPrefix(strLine, "[["):
+			//array of tables is not editable
+			arrayTable = true
+			strName := strings.TrimPrefix(strLine, "[[")
+			if idx := strings.Index(strName, "]]"); idx >= 0 {
+				strName = strName[:idx]
+			}
+			d.arrays[strings.Join(splitTOMLKey(strName), ".")] = true
+		case strings.HasPrefix(strLine, "["):
+			arrayTable = false
+			strName := strLine[1:]
+			if idx := strings.IndexByte(strName, ']'); idx >= 0 {
+				strName = strName[:idx]
+			}
+			section = splitTOMLKey(strName)
+			strSection = strings.Join(section, ".")
+			d.tables[strSection] = &tomlTable{insert: lineEnd}
+		default:
+			eq := indexOutsideQuotes(strText[pos:lineEnd], '=')
+			if eq < 0 {
+				break
+			}
+			start := skipSpaces(strText, pos+eq+1)
+			end, valueLineEnd := scanTOMLValue(strText, start)
+			if !arrayTable {
+				path := append(append([]string{}, section...), splitTOMLKey(strText[pos:pos+eq])...)
+				for i := len(section) + 1; i < len(path); i++ {
+					d.dotted[strings.Join(path[:i], ".")] = section
+				}
+				d.entries[strings.Join(path, ".")] = &tomlEntry{start: start, end: end}
+				t := d.tables[strSection]
+				t.insert, t.hasKeys = valueLineEnd, true
+			}
+			lineEnd = valueLineEnd
+		}
+		pos = lineEnd + 1
+	}
+}
+
+// scanTOMLValue returns end offset of value starting at start and end offset of the line the value ends on.
+// multi-line strings and arrays/inline tables spanning lines are scanned as a whole.
+func scanTOMLValue(strText string, start int) (end, lineEnd int) {
+	var depth int
+	end = start
+	for i := start; i < len(strText); {
+		c := strText[i]
+		switch {
+		case strings.HasPrefix(strText[i:], `"""`), strings.HasPrefix(strText[i:], `'''`):
+			i = closingQuote(strText, i+3, strText[i:i+3])
+			end = i
+			continue
+		case c == '"', c == '\'':
+			i = closingQuote(strText, i+1, string(c))
+			end = i
+			continue
+		case c == '#':
+			for i < len(strText) && strText[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '[', c == '{':
+			depth++
+		case c == ']', c == '}':
+			depth--
+		case c == '\n':
+			if depth <= 0 {
+				return end, i
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			end = i + 1
+		}
+		i++
+	}
+	return end, len(strText)
+}
+
+// closingQuote returns offset after closing quote, basic strings honor backslash escapes
+func closingQuote(strText string, i int, strQuote string) int {
+	for i < len(strText) {
+		if strQuote[0] == '"' && strText[i] == '\\' {
+			i += 2
+			continue
+		}
+		if len(strQuote) == 1 && strText[i] == '\n' {
+			return i
+		}
+		if strings.HasPrefix(strText[i:], strQuote) {
+			return i + len(strQuote)
+		}
+		i++
+	}
+	return len(strText)
+}
+
+func indexOutsideQuotes(s string, c byte) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// splitTOMLKey returns parts of dotted key without quotes and spaces, eg. ` "a" . b ` => [a b]
+func splitTOMLKey(strKey string) []string {
+	var parts []string
+	for {
+		idx := indexOutsideQuotes(strKey, '.')
+		if idx < 0 {
+			break
+		}
+		parts = append(parts, strKey[:idx])
+		strKey = strKey[idx+1:]
+	}
+	parts = append(parts, strKey)
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if len(p) >= 2 && (p[0] == '"' || p[0] == '\'') && p[len(p)-1] == p[0] {
+			p = p[1 : len(p)-1]
+		}
+		parts[i] = p
+	}
+	return parts
+}
+
+func formatTOMLKey(path []string) string {
+	var parts []string
+	for _, p := range path {
+		if bareKeyRegexp.MatchString(p) {
+			parts = append(parts, p)
+		} else {
+			parts = append(parts, quoteTOMLString(p))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func formatTOMLValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return quoteTOMLString(val), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val), nil
+	case float32:
+		return formatTOMLValue(float64(val))
+	case float64:
+		s := strconv.FormatFloat(val, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s, nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		var items []string
+		for i := 0; i < rv.Len(); i++ {
+			s, err := formatTOMLValue(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("unsupported TOML value type %T", v)
+}
+
+func quoteTOMLString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				sb.WriteString(fmt.Sprintf(`\u%04X`, r))
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// equalTOMLValue reports whether two TOML value literals decode to the same value, eg. 'abc' and "abc"
+func equalTOMLValue(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ta, err := toml.Load("v = " + a)
+	if err != nil {
+		return false
+	}
+	tb, err := toml.Load("v = " + b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(ta.Get("v"), tb.Get("v"))
+}
@@ -0,0 +1,139 @@
+package confile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/require"
+)
+
+const testTOML = `# This is a TOML config file.
+
+# TCP or UNIX socket address of the ABCI application
+proxy_app = "tcp://127.0.0.1:26658"
+
+#######################################################
+###           P2P Configuration Options             ###
+#######################################################
+[p2p]
+
+# Address to listen for incoming connections
+laddr = "tcp://0.0.0.0:26656"
+
+# Comma separated list of nodes to keep persistent connections to
+persistent_peers = ""
+
+seeds = 'abc' # literal string
+
+[telemetry]
+enabled = false
+global-labels = [
+  ["chain_id", "hobby"], # first label
+]
+
+[[arrays]]
+name = "untouched"
+`
+
+func TestTOMLDocumentSet(t *testing.T) {
+	doc := NewTOMLDocument([]byte(testTOML))
+
+	changed, err := doc.SetMap(map[string]interface{}{
+		"p2p": map[string]interface{}{
+			"persistent_peers": "id@127.0.0.1:26656",
+			"seeds":            "abc",
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, changed)
+	expected := strings.Replace(testTOML, `persistent_peers = ""`, `persistent_peers = "id@127.0.0.1:26656"`, 1)
+	require.Equal(t, expected, string(doc.Bytes()))
+
+	//equal values leave document untouched
+	changed, err = doc.Set([]string{"p2p", "seeds"}, "abc")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	//trailing comment kept
+	_, err = doc.Set([]string{"p2p", "seeds"}, "xyz")
+	require.NoError(t, err)
+	require.Contains(t, string(doc.Bytes()), `seeds = "xyz" # literal string`)
+
+	//multi-line array replaced as a whole
+	_, err = doc.Set([]string{"telemetry", "global-labels"}, []interface{}{})
+	require.NoError(t, err)
+	require.Contains(t, string(doc.Bytes()), "enabled = false\nglobal-labels = []\n\n[[arrays]]")
+
+	//missing key inserted after last key of table
+	_, err = doc.Set([]string{"p2p", "pex"}, true)
+	require.NoError(t, err)
+	require.Contains(t, string(doc.Bytes()), `seeds = "xyz" # literal string`+"\npex = true\n\n[telemetry]")
+
+	//missing table appended
+	_, err = doc.Set([]string{"api", "address"}, "tcp://0.0.0.0:1317")
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(doc.Bytes()), "name = \"untouched\"\n\n[api]\naddress = \"tcp://0.0.0.0:1317\"\n"))
+
+	//root key inserted after last root key and keys of array tables are not editable
+	_, err = doc.Set([]string{"moniker"}, "node0")
+	require.NoError(t, err)
+	require.Contains(t, string(doc.Bytes()), "proxy_app = \"tcp://127.0.0.1:26658\"\nmoniker = \"node0\"\n")
+	_, err = doc.Set([]string{"arrays", "name"}, "changed")
+	require.Error(t, err)
+	require.Contains(t, string(doc.Bytes()), `name = "untouched"`)
+
+	tree, err := toml.LoadBytes(doc.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "id@127.0.0.1:26656", tree.Get("p2p.persistent_peers"))
+	require.Equal(t, "xyz", tree.Get("p2p.seeds"))
+	require.Equal(t, "tcp://0.0.0.0:1317", tree.Get("api.address"))
+}
+
+func TestTOMLDocumentKeys(t *testing.T) {
+	doc := NewTOMLDocument([]byte("log.level = \"info\"\n\n[Server]\nAddr = \"x\"\nhttp.port = 80\n"))
+
+	//keys are case-sensitive
+	changed, err := doc.Set([]string{"Server", "Addr"}, "y")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, string(doc.Bytes()), "[Server]\nAddr = \"y\"\n")
+	_, err = doc.Set([]string{"server", "addr"}, "z")
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(doc.Bytes()), "\n[server]\naddr = \"z\"\n"))
+
+	//tables defined by dotted keys get dotted keys instead of a table header
+	_, err = doc.Set([]string{"log", "format"}, "json")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(doc.Bytes()), "log.level = \"info\"\nlog.format = \"json\"\n"))
+	_, err = doc.Set([]string{"Server", "http", "host"}, "localhost")
+	require.NoError(t, err)
+	require.Contains(t, string(doc.Bytes()), "http.port = 80\nhttp.host = \"localhost\"\n")
+	require.NotContains(t, string(doc.Bytes()), "[log]")
+
+	tree, err := toml.LoadBytes(doc.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "json", tree.Get("log.format"))
+	require.Equal(t, "y", tree.Get("Server.Addr"))
+	require.Equal(t, "z", tree.Get("server.addr"))
+	require.Equal(t, "localhost", tree.Get("Server.http.host"))
+}
+
+func TestEditTOML(t *testing.T) {
+	strPath := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(strPath, []byte(testTOML), 0o600))
+
+	cf := New(DefaultTOMLEncodingCreator, strPath)
+	require.NoError(t, cf.EditTOML(map[string]interface{}{
+		"proxy_app": "tcp://127.0.0.1:26658",
+		"telemetry": map[interface{}]interface{}{"enabled": true},
+	}))
+	data, err := os.ReadFile(strPath)
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(testTOML, "enabled = false", "enabled = true", 1), string(data))
+	fi, err := os.Stat(strPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
@@ -0,0 +1,143 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// GenesisMutator customizes genesis app_state of a chain module, chain specific packages register their
+// mutators under a plugin name in init() and are linked into the command by a blank import. mutators of a plugin
+// only run when the plugin is enabled by plugins list of config file.
+type GenesisMutator interface {
+	// Module returns app_state key of the module, eg. feemarket
+	Module() string
+	// Mutate transforms state of the module in place, appState is the whole app_state for reading other modules
+	Mutate(state, appState map[string]interface{}) error
+	// Validate checks state of the module after all mutators ran
+	Validate(state map[string]interface{}) error
+}
+
+// GenesisRegistry holds genesis mutators and their plugin names by module
+type GenesisRegistry struct {
+	locker   sync.RWMutex
+	mutators map[string]GenesisMutator
+	plugins  map[string]string
+}
+
+var defaultRegistry = NewGenesisRegistry()
+
+func NewGenesisRegistry() *GenesisRegistry {
+	return &GenesisRegistry{
+		mutators: make(map[string]GenesisMutator),
+		plugins:  make(map[string]string),
+	}
+}
+
+// RegisterGenesisMutator registers mutator of plugin into default registry, it panics if the module is registered
+// already
+func RegisterGenesisMutator(strPlugin string, m GenesisMutator) {
+	defaultRegistry.Register(strPlugin, m)
+}
+
+// MutateGenesis applies mutators of enabled plugins of default registry to genesis
+func MutateGenesis(genesis map[string]interface{}, enabled []string) error {
+	return defaultRegistry.Mutate(genesis, enabled)
+}
+
+// GenesisModules returns modules of default registry
+func GenesisModules() []string {
+	return defaultRegistry.Modules()
+}
+
+// GenesisPlugins returns plugins of default registry
+func GenesisPlugins() []string {
+	return defaultRegistry.Plugins()
+}
+
+func (r *GenesisRegistry) Register(strPlugin string, m GenesisMutator) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	if _, ok := r.mutators[m.Module()]; ok {
+		panic(fmt.Sprintf("genesis mutator of module %s registered already", m.Module()))
+	}
+	r.mutators[m.Module()] = m
+	r.plugins[m.Module()] = strPlugin
+}
+
+// Modules returns registered modules in sorted order
+func (r *GenesisRegistry) Modules() []string {
+	r.locker.RLock()
+	defer r.locker.RUnlock()
+	var modules []string
+	for strModule := range r.mutators {
+		modules = append(modules, strModule)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// Plugins returns names of plugins having registered mutators in sorted order
+func (r *GenesisRegistry) Plugins() []string {
+	r.locker.RLock()
+	defer r.locker.RUnlock()
+	var seen = make(map[string]bool)
+	var plugins []string
+	for _, strPlugin := range r.plugins {
+		if !seen[strPlugin] {
+			seen[strPlugin] = true
+			plugins = append(plugins, strPlugin)
+		}
+	}
+	sort.Strings(plugins)
+	return plugins
+}
+
+// Mutate runs Mutate of every module of enabled plugins present in app_state of genesis in module order, then
+// Validate of them. modules absent in app_state are skipped, an enabled plugin not registered is an error.
+func (r *GenesisRegistry) Mutate(genesis map[string]interface{}, enabled []string) (err error) {
+	var plugins = make(map[string]bool)
+	for _, strPlugin := range r.Plugins() {
+		plugins[strPlugin] = false
+	}
+	for _, strPlugin := range enabled {
+		if _, ok := plugins[strPlugin]; !ok {
+			return fmt.Errorf("genesis plugin %s not found, registered plugins %v", strPlugin, r.Plugins())
+		}
+		plugins[strPlugin] = true
+	}
+	appState, ok := genesis["app_state"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var mutators []GenesisMutator
+	for _, strModule := range r.Modules() {
+		r.locker.RLock()
+		m, strPlugin := r.mutators[strModule], r.plugins[strModule]
+		r.locker.RUnlock()
+		if _, ok = appState[strModule].(map[string]interface{}); ok && plugins[strPlugin] {
+			mutators = append(mutators, m)
+		}
+	}
+	for _, m := range mutators {
+		if err = m.Mutate(appState[m.Module()].(map[string]interface{}), appState); err != nil {
+			return fmt.Errorf("genesis module %s: %s", m.Module(), err)
+		}
+	}
+	for _, m := range mutators {
+		if err = m.Validate(appState[m.Module()].(map[string]interface{})); err != nil {
+			return fmt.Errorf("genesis module %s: %s", m.Module(), err)
+		}
+	}
+	return nil
+}
+
+// DecodeState decodes module state into typed struct v by its json tags
+func DecodeState(state map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
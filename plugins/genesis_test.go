@@ -0,0 +1,69 @@
+package plugins
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testMutator struct {
+	module string
+	calls  *[]string
+}
+
+func (m *testMutator) Module() string {
+	return m.module
+}
+
+func (m *testMutator) Mutate(state, appState map[string]interface{}) error {
+	*m.calls = append(*m.calls, "mutate "+m.module)
+	state["mutated"] = true
+	return nil
+}
+
+func (m *testMutator) Validate(state map[string]interface{}) error {
+	*m.calls = append(*m.calls, "validate "+m.module)
+	if state["invalid"] != nil {
+		return fmt.Errorf("invalid state")
+	}
+	return nil
+}
+
+func TestGenesisRegistry(t *testing.T) {
+	var calls []string
+	r := NewGenesisRegistry()
+	r.Register("evmos", &testMutator{module: "feemarket", calls: &calls})
+	r.Register("evmos", &testMutator{module: "claims", calls: &calls})
+	r.Register("evmos", &testMutator{module: "absent", calls: &calls})
+	r.Register("other", &testMutator{module: "bank", calls: &calls})
+	require.Equal(t, []string{"absent", "bank", "claims", "feemarket"}, r.Modules())
+	require.Equal(t, []string{"evmos", "other"}, r.Plugins())
+	require.Panics(t, func() { r.Register("other", &testMutator{module: "claims", calls: &calls}) })
+
+	genesis := map[string]interface{}{
+		"app_state": map[string]interface{}{
+			"claims":    map[string]interface{}{},
+			"feemarket": map[string]interface{}{},
+			"bank":      map[string]interface{}{},
+		},
+	}
+	//plugins not enabled leave genesis alone
+	require.NoError(t, r.Mutate(genesis, nil))
+	require.Empty(t, calls)
+	require.NotContains(t, genesis["app_state"].(map[string]interface{})["claims"], "mutated")
+	require.EqualError(t, r.Mutate(genesis, []string{"missing"}), "genesis plugin missing not found, registered plugins [evmos other]")
+
+	require.NoError(t, r.Mutate(genesis, []string{"evmos"}))
+	require.Equal(t, []string{"mutate claims", "mutate feemarket", "validate claims", "validate feemarket"}, calls)
+	appState := genesis["app_state"].(map[string]interface{})
+	require.Equal(t, true, appState["claims"].(map[string]interface{})["mutated"])
+	require.Nil(t, appState["bank"].(map[string]interface{})["mutated"])
+	require.NotContains(t, appState, "absent")
+
+	appState["feemarket"].(map[string]interface{})["invalid"] = true
+	require.EqualError(t, r.Mutate(genesis, []string{"evmos"}), "genesis module feemarket: invalid state")
+
+	//genesis without app_state is left alone
+	require.NoError(t, r.Mutate(map[string]interface{}{}, []string{"evmos"}))
+}
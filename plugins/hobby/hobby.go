@@ -0,0 +1,196 @@
+// Package hobby registers genesis mutators of modules of hobby chain (claims, evm, feemarket and hobby) as plugin
+// hobby, link it into the command by a blank import and enable it by "plugins: [hobby]" of config file.
+package hobby
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civet148/cosmos-cli/plugins"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	PluginName = "hobby"
+
+	ModuleClaims    = "claims"
+	ModuleEvm       = "evm"
+	ModuleFeemarket = "feemarket"
+	ModuleHobby     = "hobby"
+)
+
+func init() {
+	plugins.RegisterGenesisMutator(PluginName, &claimsMutator{})
+	plugins.RegisterGenesisMutator(PluginName, &evmMutator{})
+	plugins.RegisterGenesisMutator(PluginName, &feemarketMutator{})
+	plugins.RegisterGenesisMutator(PluginName, &hobbyMutator{})
+}
+
+type claimsMutator struct{}
+
+func (m *claimsMutator) Module() string {
+	return ModuleClaims
+}
+
+// Mutate defaults claims denom to evm denom (or bond denom)
+func (m *claimsMutator) Mutate(state, appState map[string]interface{}) error {
+	params := subMap(state, "params")
+	if params != nil && (params["claims_denom"] == nil || params["claims_denom"] == "") {
+		if strDenom := evmDenom(appState); strDenom != "" {
+			params["claims_denom"] = strDenom
+		}
+	}
+	return nil
+}
+
+func (m *claimsMutator) Validate(state map[string]interface{}) error {
+	var claims ClaimsState
+	if err := plugins.DecodeState(state, &claims); err != nil {
+		return err
+	}
+	p := claims.Params
+	if p.EnableClaims && p.ClaimsDenom == "" {
+		return fmt.Errorf("claims_denom is empty")
+	}
+	for _, strDuration := range []string{p.DurationOfDecay, p.DurationUntilDecay} {
+		if strDuration == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(strDuration); err != nil {
+			return fmt.Errorf("invalid duration %q", strDuration)
+		}
+	}
+	return nil
+}
+
+type evmMutator struct{}
+
+func (m *evmMutator) Module() string {
+	return ModuleEvm
+}
+
+// Mutate defaults evm denom to bond denom of staking
+func (m *evmMutator) Mutate(state, appState map[string]interface{}) error {
+	params := subMap(state, "params")
+	if params != nil && (params["evm_denom"] == nil || params["evm_denom"] == "") {
+		if strDenom := bondDenom(appState); strDenom != "" {
+			params["evm_denom"] = strDenom
+		}
+	}
+	return nil
+}
+
+func (m *evmMutator) Validate(state map[string]interface{}) error {
+	var evm EvmState
+	if err := plugins.DecodeState(state, &evm); err != nil {
+		return err
+	}
+	if evm.Params.EvmDenom == "" {
+		return fmt.Errorf("evm_denom is empty")
+	}
+	for strKey, v := range evm.Params.ChainConfig {
+		if !strings.HasSuffix(strKey, "_block") || v == nil {
+			continue
+		}
+		if n, err := strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64); err != nil || n < 0 {
+			return fmt.Errorf("chain_config %s %v is not a block height", strKey, v)
+		}
+	}
+	return nil
+}
+
+type feemarketMutator struct{}
+
+func (m *feemarketMutator) Module() string {
+	return ModuleFeemarket
+}
+
+func (m *feemarketMutator) Mutate(state, appState map[string]interface{}) error {
+	return nil
+}
+
+func (m *feemarketMutator) Validate(state map[string]interface{}) error {
+	var feemarket FeemarketState
+	if err := plugins.DecodeState(state, &feemarket); err != nil {
+		return err
+	}
+	p := feemarket.Params
+	if !p.NoBaseFee && p.BaseFeeChangeDenominator <= 0 {
+		return fmt.Errorf("base_fee_change_denominator must be positive")
+	}
+	if p.ElasticityMultiplier < 0 {
+		return fmt.Errorf("elasticity_multiplier must not be negative")
+	}
+	if p.MinGasMultiplier != "" {
+		dec, err := sdk.NewDecFromStr(p.MinGasMultiplier.String())
+		if err != nil || dec.IsNegative() || dec.GT(sdk.OneDec()) {
+			return fmt.Errorf("min_gas_multiplier %s must be in [0, 1]", p.MinGasMultiplier)
+		}
+	}
+	return nil
+}
+
+type hobbyMutator struct{}
+
+func (m *hobbyMutator) Module() string {
+	return ModuleHobby
+}
+
+// Mutate formats exchange ratio as 18 decimals like the chain exports it (eg. 10.0 => 10.000000000000000000)
+// so genesis hash does not depend on how the ratio is written in config file, and makes allow list not null
+func (m *hobbyMutator) Mutate(state, appState map[string]interface{}) error {
+	exchange := subMap(subMap(state, "params"), "exchange")
+	if exchange == nil {
+		return nil
+	}
+	if v, ok := exchange["exchange_ratio"]; ok && v != nil {
+		dec, err := sdk.NewDecFromStr(fmt.Sprintf("%v", v))
+		if err != nil {
+			return fmt.Errorf("invalid exchange_ratio %v", v)
+		}
+		exchange["exchange_ratio"] = dec.String()
+	}
+	if exchange["allow_list"] == nil {
+		exchange["allow_list"] = []interface{}{}
+	}
+	return nil
+}
+
+func (m *hobbyMutator) Validate(state map[string]interface{}) error {
+	var hobby HobbyState
+	if err := plugins.DecodeState(state, &hobby); err != nil {
+		return err
+	}
+	ex := hobby.Params.Exchange
+	if ex.FromDenom == "" || ex.ToDenom == "" {
+		return fmt.Errorf("exchange from_denom and to_denom must not be empty")
+	}
+	if ex.FromDenom == ex.ToDenom {
+		return fmt.Errorf("exchange from_denom and to_denom are both %s", ex.FromDenom)
+	}
+	dec, err := sdk.NewDecFromStr(ex.ExchangeRatio)
+	if err != nil || !dec.IsPositive() {
+		return fmt.Errorf("exchange_ratio %q must be positive", ex.ExchangeRatio)
+	}
+	return nil
+}
+
+// subMap returns child map of key, nil if missing
+func subMap(m map[string]interface{}, strKey string) map[string]interface{} {
+	child, _ := m[strKey].(map[string]interface{})
+	return child
+}
+
+func bondDenom(appState map[string]interface{}) string {
+	strDenom, _ := subMap(subMap(appState, "staking"), "params")["bond_denom"].(string)
+	return strDenom
+}
+
+func evmDenom(appState map[string]interface{}) string {
+	if strDenom, _ := subMap(subMap(appState, ModuleEvm), "params")["evm_denom"].(string); strDenom != "" {
+		return strDenom
+	}
+	return bondDenom(appState)
+}
@@ -0,0 +1,69 @@
+package hobby
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+const testGenesis = `{
+  "app_state": {
+    "staking": {"params": {"bond_denom": "uhby"}},
+    "evm": {"params": {"evm_denom": "", "chain_config": {"london_block": "0", "dao_fork_block": null, "dao_fork_support": true}}},
+    "claims": {"params": {"claims_denom": "", "enable_claims": true, "duration_of_decay": "2629800s"}},
+    "feemarket": {"params": {"base_fee_change_denominator": 8, "elasticity_multiplier": 2, "enable_height": 0, "min_gas_multiplier": "0.5"}},
+    "hobby": {"params": {"exchange": {"from_denom": "uhby", "to_denom": "usby", "exchange_ratio": "10.0"}}}
+  }
+}`
+
+func loadTestGenesis(t *testing.T) map[string]interface{} {
+	var genesis map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(testGenesis))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&genesis))
+	return genesis
+}
+
+func params(genesis map[string]interface{}, strModule string) map[string]interface{} {
+	return subMap(subMap(subMap(genesis, "app_state"), strModule), "params")
+}
+
+func TestMutateGenesis(t *testing.T) {
+	require.Equal(t, []string{ModuleClaims, ModuleEvm, ModuleFeemarket, ModuleHobby}, plugins.GenesisModules())
+	require.Equal(t, []string{PluginName}, plugins.GenesisPlugins())
+	genesis := loadTestGenesis(t)
+	//linked but not enabled by config file
+	require.NoError(t, plugins.MutateGenesis(genesis, nil))
+	require.Equal(t, "", params(genesis, ModuleEvm)["evm_denom"])
+	require.NoError(t, plugins.MutateGenesis(genesis, []string{PluginName}))
+	require.Equal(t, "uhby", params(genesis, ModuleEvm)["evm_denom"])
+	require.Equal(t, "uhby", params(genesis, ModuleClaims)["claims_denom"])
+	exchange := subMap(params(genesis, ModuleHobby), "exchange")
+	require.Equal(t, "10.000000000000000000", exchange["exchange_ratio"])
+	require.Equal(t, []interface{}{}, exchange["allow_list"])
+}
+
+func TestValidateGenesis(t *testing.T) {
+	var cases = []struct {
+		module string
+		key    string
+		value  interface{}
+		err    string
+	}{
+		{ModuleHobby, "exchange", map[string]interface{}{"from_denom": "uhby", "to_denom": "uhby", "exchange_ratio": "1"}, "genesis module hobby: exchange from_denom and to_denom are both uhby"},
+		{ModuleHobby, "exchange", map[string]interface{}{"from_denom": "uhby", "to_denom": "usby", "exchange_ratio": "0"}, `genesis module hobby: exchange_ratio "0.000000000000000000" must be positive`},
+		{ModuleHobby, "exchange", map[string]interface{}{"from_denom": "uhby", "to_denom": "usby", "exchange_ratio": "x"}, "genesis module hobby: invalid exchange_ratio x"},
+		{ModuleEvm, "chain_config", map[string]interface{}{"london_block": "-1"}, "genesis module evm: chain_config london_block -1 is not a block height"},
+		{ModuleClaims, "duration_of_decay", "1 month", `genesis module claims: invalid duration "1 month"`},
+		{ModuleFeemarket, "min_gas_multiplier", "1.5", "genesis module feemarket: min_gas_multiplier 1.5 must be in [0, 1]"},
+		{ModuleFeemarket, "base_fee_change_denominator", 0, "genesis module feemarket: base_fee_change_denominator must be positive"},
+	}
+	for _, c := range cases {
+		genesis := loadTestGenesis(t)
+		params(genesis, c.module)[c.key] = c.value
+		require.EqualError(t, plugins.MutateGenesis(genesis, []string{PluginName}), c.err, c.module)
+	}
+}
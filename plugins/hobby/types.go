@@ -0,0 +1,56 @@
+package hobby
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ClaimsState struct {
+	ClaimsRecords []interface{} `json:"claims_records" yaml:"claims_records"`
+	Params        struct {
+		AirdropStartTime   time.Time `json:"airdrop_start_time" yaml:"airdrop_start_time"`
+		AuthorizedChannels []string  `json:"authorized_channels" yaml:"authorized_channels"`
+		ClaimsDenom        string    `json:"claims_denom" yaml:"claims_denom"`
+		DurationOfDecay    string    `json:"duration_of_decay" yaml:"duration_of_decay"`
+		DurationUntilDecay string    `json:"duration_until_decay" yaml:"duration_until_decay"`
+		EnableClaims       bool      `json:"enable_claims" yaml:"enable_claims"`
+		EvmChannels        []string  `json:"evm_channels" yaml:"evm_channels"`
+	} `json:"params" yaml:"params"`
+}
+
+type EvmState struct {
+	Accounts []interface{} `json:"accounts"`
+	Params   struct {
+		ActivePrecompiles   []string               `json:"active_precompiles" yaml:"active_precompiles"`
+		AllowUnprotectedTxs bool                   `json:"allow_unprotected_txs" yaml:"allow_unprotected"`
+		ChainConfig         map[string]interface{} `json:"chain_config"` //fork blocks eg. london_block, eip150_hash and dao_fork_support
+		EnableCall          bool                   `json:"enable_call" yaml:"enable_call"`
+		EnableCreate        bool                   `json:"enable_create" yaml:"enable_create"`
+		EvmDenom            string                 `json:"evm_denom" yaml:"evm_denom"`
+		ExtraEips           []interface{}          `json:"extra_eips" yaml:"extra_eips"`
+	} `json:"params"`
+}
+
+type FeemarketState struct {
+	BlockGas json.Number `json:"block_gas" yaml:"block_gas"`
+	Params   struct {
+		BaseFee                  json.Number `json:"base_fee" yaml:"base_fee"`
+		BaseFeeChangeDenominator int         `json:"base_fee_change_denominator" yaml:"base_fee_change_denominator"`
+		ElasticityMultiplier     int         `json:"elasticity_multiplier" yaml:"elasticity_multiplier"`
+		EnableHeight             json.Number `json:"enable_height" yaml:"enable_height"`
+		MinGasMultiplier         json.Number `json:"min_gas_multiplier" yaml:"min_gas_multiplier"`
+		MinGasPrice              json.Number `json:"min_gas_price" yaml:"min_gas_price"`
+		NoBaseFee                bool        `json:"no_base_fee" yaml:"no_base_fee"`
+	} `json:"params" yaml:"params"`
+}
+
+type HobbyState struct {
+	Params struct {
+		Exchange struct {
+			FromDenom     string   `json:"from_denom" yaml:"from_denom"`
+			ToDenom       string   `json:"to_denom" yaml:"to_denom"`
+			ExchangeRatio string   `json:"exchange_ratio" yaml:"exchange_ratio"`
+			AllowList     []string `json:"allow_list" yaml:"allow_list"`
+		} `json:"exchange" yaml:"exchange"`
+	} `json:"params" yaml:"params"`
+}
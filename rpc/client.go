@@ -0,0 +1,213 @@
+// Package rpc is a minimal client of CometBFT JSON-RPC endpoints exposed by validator nodes.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// BlockIDFlagCommit is block id flag of a signature voted for the committed block
+const BlockIDFlagCommit = 2
+
+// Client queries a node RPC endpoint
+type Client struct {
+	strAddr string
+	client  *http.Client
+}
+
+// NewClient returns a client of RPC endpoint, tcp:// scheme is translated to http://
+func NewClient(strAddr string) *Client {
+	if strings.HasPrefix(strAddr, "tcp://") {
+		strAddr = "http://" + strings.TrimPrefix(strAddr, "tcp://")
+	} else if !strings.Contains(strAddr, "://") {
+		strAddr = "http://" + strAddr
+	}
+	return &Client{
+		strAddr: strings.TrimSuffix(strAddr, "/"),
+		client:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Addr returns http address of RPC endpoint
+func (c *Client) Addr() string {
+	return c.strAddr
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// StatusResult is result of /status
+type StatusResult struct {
+	NodeInfo struct {
+		ID      string `json:"id"`
+		Network string `json:"network"`
+		Moniker string `json:"moniker"`
+		Version string `json:"version"`
+	} `json:"node_info"`
+	SyncInfo struct {
+		LatestBlockHash   string    `json:"latest_block_hash"`
+		LatestAppHash     string    `json:"latest_app_hash"`
+		LatestBlockHeight int64     `json:"latest_block_height,string"`
+		LatestBlockTime   time.Time `json:"latest_block_time"`
+		CatchingUp        bool      `json:"catching_up"`
+	} `json:"sync_info"`
+	ValidatorInfo struct {
+		Address     string `json:"address"`
+		VotingPower int64  `json:"voting_power,string"`
+	} `json:"validator_info"`
+}
+
+// Status queries node status
+func (c *Client) Status(ctx context.Context) (*StatusResult, error) {
+	var result StatusResult
+	if err := c.call(ctx, "status", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// NetInfoResult is result of /net_info
+type NetInfoResult struct {
+	Listening bool `json:"listening"`
+	NPeers    int  `json:"n_peers,string"`
+	Peers     []struct {
+		NodeInfo struct {
+			ID      string `json:"id"`
+			Moniker string `json:"moniker"`
+		} `json:"node_info"`
+		RemoteIP string `json:"remote_ip"`
+	} `json:"peers"`
+}
+
+// NetInfo queries node network info
+func (c *Client) NetInfo(ctx context.Context) (*NetInfoResult, error) {
+	var result NetInfoResult
+	if err := c.call(ctx, "net_info", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Validator is a validator of /validators
+type Validator struct {
+	Address     string `json:"address"`
+	VotingPower int64  `json:"voting_power,string"`
+}
+
+// ValidatorsResult is result of /validators
+type ValidatorsResult struct {
+	BlockHeight int64       `json:"block_height,string"`
+	Validators  []Validator `json:"validators"`
+	Count       int         `json:"count,string"`
+	Total       int         `json:"total,string"`
+}
+
+// Validators queries validator set at height (latest if height is 0), all pages are fetched
+func (c *Client) Validators(ctx context.Context, nHeight int64) (*ValidatorsResult, error) {
+	var all ValidatorsResult
+	for nPage := 1; ; nPage++ {
+		var result ValidatorsResult
+		params := url.Values{}
+		if nHeight > 0 {
+			params.Set("height", strconv.FormatInt(nHeight, 10))
+		}
+		params.Set("page", strconv.Itoa(nPage))
+		params.Set("per_page", "100")
+		if err := c.call(ctx, "validators", params, &result); err != nil {
+			return nil, err
+		}
+		all.BlockHeight, all.Total = result.BlockHeight, result.Total
+		all.Validators = append(all.Validators, result.Validators...)
+		if len(result.Validators) == 0 || len(all.Validators) >= result.Total {
+			break
+		}
+	}
+	all.Count = len(all.Validators)
+	return &all, nil
+}
+
+// CommitResult is result of /commit
+type CommitResult struct {
+	SignedHeader struct {
+		Header struct {
+			Height  int64  `json:"height,string"`
+			AppHash string `json:"app_hash"`
+		} `json:"header"`
+		Commit struct {
+			BlockID struct {
+				Hash string `json:"hash"`
+			} `json:"block_id"`
+			Signatures []struct {
+				BlockIDFlag      int    `json:"block_id_flag"`
+				ValidatorAddress string `json:"validator_address"`
+			} `json:"signatures"`
+		} `json:"commit"`
+	} `json:"signed_header"`
+	Canonical bool `json:"canonical"`
+}
+
+// Signed reports whether validator of address signed the commit
+func (r *CommitResult) Signed(strAddr string) bool {
+	for _, s := range r.SignedHeader.Commit.Signatures {
+		if s.BlockIDFlag == BlockIDFlagCommit && strings.EqualFold(s.ValidatorAddress, strAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit queries commit of block at height (latest if height is 0)
+func (c *Client) Commit(ctx context.Context, nHeight int64) (*CommitResult, error) {
+	var result CommitResult
+	params := url.Values{}
+	if nHeight > 0 {
+		params.Set("height", strconv.FormatInt(nHeight, 10))
+	}
+	if err := c.call(ctx, "commit", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) call(ctx context.Context, strMethod string, params url.Values, result interface{}) error {
+	strURL := fmt.Sprintf("%s/%s", c.strAddr, strMethod)
+	if len(params) != 0 {
+		strURL += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var r response
+	if err = json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("%s %s unexpected response (http status %d): %s", c.strAddr, strMethod, resp.StatusCode, err)
+	}
+	if r.Error != nil {
+		return fmt.Errorf("%s %s error code %d %s %s", c.strAddr, strMethod, r.Error.Code, r.Error.Message, r.Error.Data)
+	}
+	return json.Unmarshal(r.Result, result)
+}
@@ -12,6 +12,8 @@ type ChainMaker struct {
 	strDefaultDenom   string
 	strKeyPhrase      string
 	strKeyringBackend string
+	dialect           *Dialect
+	strGasPrices      string
 }
 
 func NewChainMaker(strNodeCmd, strChainID, strDefaultDenom, strKeyPhrase, strKeyringBackend string) *ChainMaker {
@@ -24,6 +26,7 @@ func NewChainMaker(strNodeCmd, strChainID, strDefaultDenom, strKeyPhrase, strKey
 		strDefaultDenom:   strDefaultDenom,
 		strKeyPhrase:      strKeyPhrase,
 		strKeyringBackend: strKeyringBackend,
+		dialect:           DialectLegacy,
 	}
 }
 
@@ -31,20 +34,58 @@ func (s *ChainMaker) NodeCmd() string {
 	return s.strNodeCmd
 }
 
-func (s *ChainMaker) CopyCmd() string {
-	return types.EXEC_CMD_COPY
+// WithNodeCmd returns a copy of chain maker running another node command, eg. a validator of different version
+func (s *ChainMaker) WithNodeCmd(strNodeCmd string) *ChainMaker {
+	if strNodeCmd == "" || strNodeCmd == s.strNodeCmd {
+		return s
+	}
+	c := *s
+	c.strNodeCmd = strNodeCmd
+	return &c
 }
 
-func (s *ChainMaker) MkdirCmd() string {
-	return types.EXEC_CMD_MKDIR
+// WithDialect returns a copy of chain maker making command lines of dialect, eg. a binary of cosmos-sdk v0.50
+func (s *ChainMaker) WithDialect(d *Dialect) *ChainMaker {
+	if d == nil || d == s.dialect {
+		return s
+	}
+	c := *s
+	c.dialect = d
+	return &c
+}
+
+// WithGasPrices returns a copy of chain maker paying transaction fees by gas prices, eg. 10000000uhby
+func (s *ChainMaker) WithGasPrices(strGasPrices string) *ChainMaker {
+	if strGasPrices == s.strGasPrices {
+		return s
+	}
+	c := *s
+	c.strGasPrices = strGasPrices
+	return &c
+}
+
+func (s *ChainMaker) Dialect() *Dialect {
+	return s.dialect
+}
+
+func (s *ChainMaker) MakeCmdLineVersion() string {
+	return fmt.Sprintf("%s version", s.NodeCmd())
+}
+
+func (s *ChainMaker) MakeCmdLineVersionLong() string {
+	return fmt.Sprintf("%s version --long", s.NodeCmd())
+}
+
+func (s *ChainMaker) MakeCmdLineHelp() string {
+	return fmt.Sprintf("%s --help", s.NodeCmd())
 }
 
-func (s *ChainMaker) MakeCmdLineConfigKeyringBackend(strHome string) string {
-	return fmt.Sprintf("%s config keyring-backend %s --home %s", s.NodeCmd(), s.strKeyringBackend, strHome)
+func (s *ChainMaker) CopyCmd() string {
+	return types.EXEC_CMD_COPY
 }
 
-func (s *ChainMaker) MakeCmdLineConfigChainID(strHome string) string {
-	return fmt.Sprintf("%s config chain-id %s --home %s", s.NodeCmd(), s.strChainID, strHome)
+func (s *ChainMaker) MkdirCmd() string {
+	return types.EXEC_CMD_MKDIR
 }
 
 func (s *ChainMaker) MakeCmdLineInit(strMoniker, strHome string) string {
@@ -133,7 +174,7 @@ func (s *ChainMaker) MakeCmdLineKeysImport(strAccName, strHome, strKeyFile strin
 
 func (s *ChainMaker) MakeCmdLineAddGenesisAccount(strAccName, strHome, strBalances string, passwd bool) string {
 	var strCmdLine string
-	strSpawn := fmt.Sprintf("%s add-genesis-account %s %s --home %s --keyring-backend %s", s.NodeCmd(), strAccName, strBalances, strHome, s.strKeyringBackend)
+	strSpawn := fmt.Sprintf("%s %s %s %s --home %s --keyring-backend %s", s.NodeCmd(), s.dialect.genesisCmd("add-genesis-account"), strAccName, strBalances, strHome, s.strKeyringBackend)
 	if passwd {
 		strCmdLine = fmt.Sprintf(`
 		expect <<-EOF
@@ -151,8 +192,8 @@ func (s *ChainMaker) MakeCmdLineAddGenesisAccount(strAccName, strHome, strBalanc
 
 func (s *ChainMaker) MakeCmdLineGenTx(strAccName, strHome, strStaking, strIP, strPort string, passwd bool) string {
 	var strCmdLine string
-	strSpawn := fmt.Sprintf("%s gentx %s %s --chain-id %s --ip %s --p2p-port %s --home %s --keyring-backend %s",
-		s.NodeCmd(), strAccName, strStaking, s.strChainID, strIP, strPort, strHome, s.strKeyringBackend)
+	strSpawn := fmt.Sprintf("%s %s %s %s --chain-id %s --ip %s --p2p-port %s --home %s --keyring-backend %s",
+		s.NodeCmd(), s.dialect.genesisCmd("gentx"), strAccName, strStaking, s.strChainID, strIP, strPort, strHome, s.strKeyringBackend)
 	if passwd {
 		strCmdLine = fmt.Sprintf(`
 		expect <<-EOF
@@ -173,11 +214,11 @@ func (s *ChainMaker) MakeCmdLineCopyGenTxJSON(strHomeSrc, strHomeDst string) str
 }
 
 func (s *ChainMaker) MakeCmdLineCollectGenTxs(strHome string) string {
-	return fmt.Sprintf("%s collect-gentxs --home %s", s.NodeCmd(), strHome)
+	return fmt.Sprintf("%s %s --home %s", s.NodeCmd(), s.dialect.genesisCmd("collect-gentxs"), strHome)
 }
 
 func (s *ChainMaker) MakeCmdLineValidateGenesis(strHome string) string {
-	return fmt.Sprintf("%s validate-genesis --home %s", s.NodeCmd(), strHome)
+	return fmt.Sprintf("%s %s --home %s", s.NodeCmd(), s.dialect.genesisCmd("validate-genesis"), strHome)
 }
 
 func (s *ChainMaker) MakeCmdLineCopyGenesisFile(strHomeSrc, strHomeDst string) string {
@@ -193,7 +234,7 @@ func (s *ChainMaker) MakeCmdLineMkdirKeyringFile(strHome string) string {
 }
 
 func (s *ChainMaker) MakeCmdLineShowNodeId(strHome string) string {
-	return fmt.Sprintf("%s tendermint show-node-id --home %s", s.NodeCmd(), strHome)
+	return fmt.Sprintf("%s %s show-node-id --home %s", s.NodeCmd(), s.dialect.Comet, strHome)
 }
 
 func (s *ChainMaker) MakeCmdLineKeysShowAddrOnly(strHome string, name string, addrType string) string {
@@ -213,3 +254,128 @@ func (s *ChainMaker) MakeCmdLineKeysShowAddrOnly(strHome string, name string, ad
 	}
 	return strCmdLine
 }
+
+func (s *ChainMaker) MakeCmdLineShowValidator(strHome string) string {
+	return fmt.Sprintf("%s %s show-validator --home %s", s.NodeCmd(), s.dialect.Comet, strHome)
+}
+
+// MakeCmdLineTxBankSend makes a bank send transaction command line. The transaction is broadcast to strNode
+// when it is not empty, otherwise an unsigned transaction is generated only.
+func (s *ChainMaker) MakeCmdLineTxBankSend(strFrom, strToAddr, strCoins, strHome, strNode, strFees string) string {
+	strSpawn := fmt.Sprintf("%s tx bank send %s %s %s %s", s.NodeCmd(), strFrom, strToAddr, strCoins, s.makeTxFlags(strHome, strNode, strFees))
+	return s.makeExpectKeyring(strSpawn)
+}
+
+// MakeCmdLineTxCreateValidator makes a staking create-validator transaction command line. The transaction is
+// broadcast to strNode when it is not empty, otherwise an unsigned transaction is generated only.
+func (s *ChainMaker) MakeCmdLineTxCreateValidator(strAccName, strHome, strAmount, strPubKey, strMoniker, strNode, strFees string) string {
+	strSpawn := fmt.Sprintf("%s tx staking create-validator --from %s --amount %s --pubkey %s --moniker %s "+
+		"--commission-rate %s --commission-max-rate %s --commission-max-change-rate %s --min-self-delegation %s %s",
+		s.NodeCmd(), strAccName, strAmount, s.quoteArg(strPubKey), strMoniker,
+		types.DEFAULT_COMMISSION_RATE, types.DEFAULT_COMMISSION_MAX_RATE, types.DEFAULT_COMMISSION_MAX_CHANGE_RATE,
+		types.DEFAULT_MIN_SELF_DELEGATION, s.makeTxFlags(strHome, strNode, strFees))
+	return s.makeExpectKeyring(strSpawn)
+}
+
+func (s *ChainMaker) makeTxFlags(strHome, strNode, strFees string) string {
+	strFlags := fmt.Sprintf("--chain-id %s --home %s --keyring-backend %s", s.strChainID, strHome, s.strKeyringBackend)
+	if strFees != "" {
+		strFlags += fmt.Sprintf(" --fees %s", strFees)
+	} else if s.strGasPrices != "" {
+		strFlags += fmt.Sprintf(" --gas-prices %s", s.strGasPrices)
+	}
+	if strNode == "" {
+		return strFlags + " --generate-only"
+	}
+	return strFlags + fmt.Sprintf(" --node %s --gas auto --gas-adjustment %s --broadcast-mode sync --output json -y", strNode, types.DEFAULT_GAS_ADJUSTMENT)
+}
+
+// needPassphrase reports whether the keyring backend prompts for a passphrase
+func (s *ChainMaker) needPassphrase() bool {
+	return s.strKeyringBackend != types.KEYRING_BACKEND_TEST
+}
+
+// quoteArg quotes an argument containing special characters for the shell (or for expect's spawn)
+func (s *ChainMaker) quoteArg(strArg string) string {
+	if s.needPassphrase() {
+		return fmt.Sprintf("{%s}", strArg)
+	}
+	return fmt.Sprintf("'%s'", strArg)
+}
+
+// makeExpectKeyring wraps spawn command with expect script to enter keyring passphrase if necessary
+func (s *ChainMaker) makeExpectKeyring(strSpawn string) string {
+	if !s.needPassphrase() {
+		return strSpawn
+	}
+	return fmt.Sprintf(`
+		expect <<-EOF
+		set timeout %d
+		spawn %s
+		expect "Enter keyring passphrase"
+		send "%s\r"
+		expect eof
+		EOF
+`, types.EXPECT_TX_TIMEOUT, strSpawn, s.strKeyPhrase)
+}
+
+// MakeCmdLineQueryTx makes command line to query a committed transaction by hash
+func (s *ChainMaker) MakeCmdLineQueryTx(strTxHash, strNode string) string {
+	return fmt.Sprintf("%s query tx %s --node %s --output json", s.NodeCmd(), strTxHash, strNode)
+}
+
+func (s *ChainMaker) MakeCmdLineQueryBalances(strAddr, strNode string) string {
+	return fmt.Sprintf("%s query bank balances %s --node %s --output json", s.NodeCmd(), strAddr, strNode)
+}
+
+// MakeCmdLineExport makes command line to export state of node home as genesis to output document, export the latest
+// height if nHeight <= 0
+func (s *ChainMaker) MakeCmdLineExport(strHome, strOutput string, nHeight int64) string {
+	if nHeight > 0 {
+		return fmt.Sprintf("%s export --home %s --height %d --output-document %s", s.NodeCmd(), strHome, nHeight, strOutput)
+	}
+	return fmt.Sprintf("%s export --home %s --output-document %s", s.NodeCmd(), strHome, strOutput)
+}
+
+func (s *ChainMaker) MakeCmdLineUnsafeResetAll(strHome string) string {
+	return fmt.Sprintf("%s %s unsafe-reset-all --home %s --keep-addr-book", s.NodeCmd(), s.dialect.Comet, strHome)
+}
+
+// MakeCmdLineTxSoftwareUpgrade makes command line to submit a software upgrade proposal
+func (s *ChainMaker) MakeCmdLineTxSoftwareUpgrade(strName string, nHeight int64, strInfo, strTitle, strDeposit, strFrom, strHome, strNode, strFees string) string {
+	d := s.dialect
+	strSpawn := fmt.Sprintf("%s %s %s --upgrade-height %d --title %s %s %s --deposit %s --from %s %s",
+		s.NodeCmd(), d.SoftwareUpgrade, strName, nHeight, s.quoteArg(strTitle), d.UpgradeSummary, s.quoteArg(strTitle), strDeposit, strFrom, s.makeTxFlags(strHome, strNode, strFees))
+	if d.NoValidate {
+		strSpawn += " --no-validate"
+	}
+	if strInfo != "" {
+		strSpawn += fmt.Sprintf(" --upgrade-info %s", s.quoteArg(strInfo))
+	}
+	return s.makeExpectKeyring(strSpawn)
+}
+
+func (s *ChainMaker) MakeCmdLineTxGovVote(strProposalID, strOption, strFrom, strHome, strNode, strFees string) string {
+	strSpawn := fmt.Sprintf("%s tx gov vote %s %s --from %s %s", s.NodeCmd(), strProposalID, strOption, strFrom, s.makeTxFlags(strHome, strNode, strFees))
+	return s.makeExpectKeyring(strSpawn)
+}
+
+// MakeCmdLineTxStakingDelegate makes a staking delegate transaction command line
+func (s *ChainMaker) MakeCmdLineTxStakingDelegate(strFrom, strValAddr, strAmount, strHome, strNode, strFees string) string {
+	strSpawn := fmt.Sprintf("%s tx staking delegate %s %s --from %s %s", s.NodeCmd(), strValAddr, strAmount, strFrom, s.makeTxFlags(strHome, strNode, strFees))
+	return s.makeExpectKeyring(strSpawn)
+}
+
+// MakeCmdLineTxGovSubmitProposal makes command line to submit a governance proposal of JSON file
+func (s *ChainMaker) MakeCmdLineTxGovSubmitProposal(strFile, strFrom, strHome, strNode, strFees string) string {
+	strProposal := s.quoteArg(strFile)
+	if s.dialect.ProposalFile != "" {
+		strProposal = s.dialect.ProposalFile + " " + strProposal
+	}
+	strSpawn := fmt.Sprintf("%s tx gov submit-proposal %s --from %s %s", s.NodeCmd(), strProposal, strFrom, s.makeTxFlags(strHome, strNode, strFees))
+	return s.makeExpectKeyring(strSpawn)
+}
+
+func (s *ChainMaker) MakeCmdLineQueryProposals(strNode string) string {
+	return fmt.Sprintf("%s query gov proposals --node %s --output json", s.NodeCmd(), strNode)
+}
@@ -0,0 +1,136 @@
+package shells
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect is command syntax of a cosmos-sdk version line
+type Dialect struct {
+	Name            string // dialect name, eg. v0.50
+	Genesis         string // parent command of genesis commands (add-genesis-account, gentx...), empty for top-level
+	Comet           string // consensus engine command, tendermint or comet
+	SoftwareUpgrade string // command to submit software upgrade proposal
+	UpgradeSummary  string // flag of software upgrade proposal summary
+	NoValidate      bool   // software upgrade command supports --no-validate
+	ProposalFile    string // flag before proposal file of submit-proposal command, empty for positional argument
+}
+
+var (
+	// DialectV045 is command syntax of cosmos-sdk v0.45
+	DialectV045 = &Dialect{
+		Name:            "v0.45",
+		Comet:           "tendermint",
+		SoftwareUpgrade: "tx gov submit-proposal software-upgrade",
+		UpgradeSummary:  "--description",
+		ProposalFile:    "--proposal",
+	}
+	// DialectV046 is command syntax of cosmos-sdk v0.46
+	DialectV046 = &Dialect{
+		Name:            "v0.46",
+		Comet:           "tendermint",
+		SoftwareUpgrade: "tx gov submit-legacy-proposal software-upgrade",
+		UpgradeSummary:  "--description",
+		NoValidate:      true,
+	}
+	// DialectV047 is command syntax of cosmos-sdk v0.47
+	DialectV047 = &Dialect{
+		Name:            "v0.47",
+		Genesis:         "genesis",
+		Comet:           "comet",
+		SoftwareUpgrade: "tx upgrade software-upgrade",
+		UpgradeSummary:  "--summary",
+		NoValidate:      true,
+	}
+	// DialectV050 is command syntax of cosmos-sdk v0.50
+	DialectV050 = &Dialect{
+		Name:            "v0.50",
+		Genesis:         "genesis",
+		Comet:           "comet",
+		SoftwareUpgrade: "tx upgrade software-upgrade",
+		UpgradeSummary:  "--summary",
+		NoValidate:      true,
+	}
+	// DialectLegacy is command syntax used before a binary is probed: top-level genesis commands and
+	// tendermint which v0.45 and many v0.47 chain apps accept
+	DialectLegacy = &Dialect{
+		Name:            "legacy",
+		Comet:           "tendermint",
+		SoftwareUpgrade: "tx upgrade software-upgrade",
+		UpgradeSummary:  "--summary",
+		NoValidate:      true,
+	}
+)
+
+var sdkVersionRegexp = regexp.MustCompile(`cosmos_sdk_version:\s*v?(\d+)\.(\d+)`)
+
+// ProbeDialect selects dialect by cosmos_sdk_version of '<binary> version --long' and adapts it to commands
+// the binary really has according to '<binary> --help', chain apps often keep commands of former versions.
+// the legacy dialect is taken if no cosmos_sdk_version found.
+func ProbeDialect(strVersionLong, strHelp string) *Dialect {
+	var d = *DialectLegacy
+	if sub := sdkVersionRegexp.FindStringSubmatch(strVersionLong); sub != nil {
+		nMajor, _ := strconv.Atoi(sub[1])
+		nMinor, _ := strconv.Atoi(sub[2])
+		switch {
+		case nMajor == 0 && nMinor < 46:
+			d = *DialectV045
+		case nMajor == 0 && nMinor < 47:
+			d = *DialectV046
+		case nMajor == 0 && nMinor < 50:
+			d = *DialectV047
+		default:
+			d = *DialectV050
+		}
+	}
+	commands := parseHelpCommands(strHelp)
+	if len(commands) == 0 {
+		return &d
+	}
+	switch {
+	case commands["add-genesis-account"]:
+		d.Genesis = ""
+	case commands["genesis"]:
+		d.Genesis = "genesis"
+	}
+	switch {
+	case commands["comet"]:
+		d.Comet = "comet"
+	case commands["tendermint"]:
+		d.Comet = "tendermint"
+	}
+	return &d
+}
+
+// parseHelpCommands returns command names of 'Available Commands' of cobra help
+func parseHelpCommands(strHelp string) map[string]bool {
+	var commands = make(map[string]bool)
+	var available bool
+	scanner := bufio.NewScanner(strings.NewReader(strHelp))
+	for scanner.Scan() {
+		strLine := scanner.Text()
+		if strings.HasPrefix(strLine, "Available Commands:") {
+			available = true
+			continue
+		}
+		if !available {
+			continue
+		}
+		fields := strings.Fields(strLine)
+		if len(fields) == 0 || !strings.HasPrefix(strLine, " ") {
+			break
+		}
+		commands[fields[0]] = true
+	}
+	return commands
+}
+
+// genesisCmd returns genesis command of dialect, eg. "genesis gentx" or "gentx"
+func (d *Dialect) genesisCmd(strCmd string) string {
+	if d.Genesis == "" {
+		return strCmd
+	}
+	return d.Genesis + " " + strCmd
+}
@@ -0,0 +1,97 @@
+package shells
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const helpV045 = `Hobby Daemon (server)
+
+Usage:
+  hobbyd [command]
+
+Available Commands:
+  add-genesis-account Add a genesis account to genesis.json
+  collect-gentxs      Collect genesis txs and output a genesis.json file
+  config              Create or query an application CLI configuration file
+  gentx               Generate a genesis tx carrying a self delegation
+  tendermint          Tendermint subcommands
+  validate-genesis    validates the genesis file at the default location or at the location passed as an arg
+
+Flags:
+  -h, --help   help for hobbyd
+`
+
+const helpV047 = `Usage:
+  simd [command]
+
+Available Commands:
+  comet       CometBFT subcommands
+  config      Create or query an application CLI configuration file
+  genesis     Application's genesis-related subcommands
+
+Flags:
+  -h, --help   help for simd
+`
+
+const helpV047Fork = `Usage:
+  hobbyd [command]
+
+Available Commands:
+  add-genesis-account Add a genesis account to genesis.json
+  genesis             Application's genesis-related subcommands
+  tendermint          Tendermint subcommands
+`
+
+func TestProbeDialect(t *testing.T) {
+	var cases = []struct {
+		name    string
+		version string
+		help    string
+		genesis string
+		comet   string
+		upgrade string
+	}{
+		{"v0.45", "name: hobby\ncosmos_sdk_version: v0.45.16\n", helpV045, "", "tendermint", "tx gov submit-proposal software-upgrade"},
+		{"v0.46", "cosmos_sdk_version: v0.46.13\n", "", "", "tendermint", "tx gov submit-legacy-proposal software-upgrade"},
+		{"v0.47", "cosmos_sdk_version: v0.47.5\n", helpV047, "genesis", "comet", "tx upgrade software-upgrade"},
+		{"v0.47 fork", "cosmos_sdk_version: v0.47.5\n", helpV047Fork, "", "tendermint", "tx upgrade software-upgrade"},
+		{"v0.50", "cosmos_sdk_version: v0.50.1\n", helpV047, "genesis", "comet", "tx upgrade software-upgrade"},
+		{"unknown version", "v1.0.0", helpV047, "genesis", "comet", "tx upgrade software-upgrade"},
+		{"no output", "", "", "", "tendermint", "tx upgrade software-upgrade"},
+		{"unparseable version", "cosmos_sdk_version: (devel)\n", "", "", "tendermint", "tx upgrade software-upgrade"},
+	}
+	for _, c := range cases {
+		d := ProbeDialect(c.version, c.help)
+		require.Equal(t, c.genesis, d.Genesis, c.name)
+		require.Equal(t, c.comet, d.Comet, c.name)
+		require.Equal(t, c.upgrade, d.SoftwareUpgrade, c.name)
+	}
+	//probing never changes dialect table
+	require.Equal(t, "", DialectV045.Genesis)
+	require.Equal(t, "comet", DialectV047.Comet)
+}
+
+func TestChainMakerDialect(t *testing.T) {
+	maker := NewChainMaker("hobbyd", "hobby_1-1", "", "12345678", "test")
+	require.Equal(t, "hobbyd add-genesis-account node1 100uhby --home /h --keyring-backend test", maker.MakeCmdLineAddGenesisAccount("node1", "/h", "100uhby", false))
+	require.Equal(t, "hobbyd tendermint show-node-id --home /h", maker.MakeCmdLineShowNodeId("/h"))
+
+	v050 := maker.WithDialect(DialectV050)
+	require.Equal(t, DialectLegacy, maker.Dialect())
+	require.Equal(t, "hobbyd genesis add-genesis-account node1 100uhby --home /h --keyring-backend test", v050.MakeCmdLineAddGenesisAccount("node1", "/h", "100uhby", false))
+	require.Equal(t, "hobbyd genesis collect-gentxs --home /h", v050.MakeCmdLineCollectGenTxs("/h"))
+	require.Equal(t, "hobbyd comet show-validator --home /h", v050.MakeCmdLineShowValidator("/h"))
+
+	v045 := maker.WithDialect(DialectV045)
+	strCmdLine := v045.MakeCmdLineTxSoftwareUpgrade("v2", 100, "", "upgrade", "10uhby", "node1", "/h", "", "")
+	require.Contains(t, strCmdLine, "hobbyd tx gov submit-proposal software-upgrade v2 --upgrade-height 100 --title 'upgrade' --description 'upgrade'")
+	require.NotContains(t, strCmdLine, "--no-validate")
+	require.Contains(t, v045.MakeCmdLineTxGovSubmitProposal("/p.json", "node1", "/h", "", ""), "hobbyd tx gov submit-proposal --proposal '/p.json' --from node1")
+
+	v046 := maker.WithDialect(DialectV046)
+	require.Contains(t, v046.MakeCmdLineTxSoftwareUpgrade("v2", 100, "", "upgrade", "10uhby", "node1", "/h", "", ""), "hobbyd tx gov submit-legacy-proposal software-upgrade v2 --upgrade-height 100 --title 'upgrade' --description 'upgrade'")
+	require.Contains(t, v046.MakeCmdLineTxGovSubmitProposal("/p.json", "node1", "/h", "", ""), "hobbyd tx gov submit-proposal '/p.json' --from node1")
+	require.Contains(t, v050.MakeCmdLineTxGovSubmitProposal("/p.json", "node1", "/h", "", ""), "hobbyd tx gov submit-proposal '/p.json' --from node1")
+}
@@ -0,0 +1,55 @@
+package shells
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SourceMaker makes command lines to build chain node binary from source repository
+type SourceMaker struct {
+	strRepo string
+}
+
+func NewSourceMaker(strRepo string) *SourceMaker {
+	return &SourceMaker{
+		strRepo: strRepo,
+	}
+}
+
+func (s *SourceMaker) MakeCmdLineGitCommit() string {
+	return fmt.Sprintf("git -C %s rev-parse HEAD", shellQuote(s.strRepo))
+}
+
+func (s *SourceMaker) MakeCmdLineGitStatus() string {
+	return fmt.Sprintf("git -C %s status --porcelain", shellQuote(s.strRepo))
+}
+
+// MakeCmdLineGoBuild builds main package of repository into output file
+func (s *SourceMaker) MakeCmdLineGoBuild(strMain, strOutput string, ldflags, tags []string) string {
+	strCmdLine := fmt.Sprintf("cd %s && go build -o %s", shellQuote(s.strRepo), shellQuote(strOutput))
+	if len(ldflags) != 0 {
+		strCmdLine += fmt.Sprintf(" -ldflags %s", shellQuote(strings.Join(ldflags, " ")))
+	}
+	if len(tags) != 0 {
+		strCmdLine += fmt.Sprintf(" -tags %s", shellQuote(strings.Join(tags, ",")))
+	}
+	return strCmdLine + " " + shellQuote(strMain)
+}
+
+// MakeCmdLineMakeInstall runs make target of repository with GOBIN redirected to output directory,
+// ldflags and tags are passed as LDFLAGS and BUILD_TAGS variables which cosmos-sdk app Makefiles honor
+func (s *SourceMaker) MakeCmdLineMakeInstall(strTarget, strGoBin string, ldflags, tags []string) string {
+	strCmdLine := fmt.Sprintf("cd %s && GOBIN=%s make %s", shellQuote(s.strRepo), shellQuote(strGoBin), strTarget)
+	if len(ldflags) != 0 {
+		strCmdLine += fmt.Sprintf(" LDFLAGS=%s", shellQuote(strings.Join(ldflags, " ")))
+	}
+	if len(tags) != 0 {
+		strCmdLine += fmt.Sprintf(" BUILD_TAGS=%s", shellQuote(strings.Join(tags, ",")))
+	}
+	return strCmdLine
+}
+
+// shellQuote quotes argument in single quotes for sh
+func shellQuote(strArg string) string {
+	return "'" + strings.ReplaceAll(strArg, "'", `'\''`) + "'"
+}
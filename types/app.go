@@ -79,3 +79,16 @@ type AppConfig struct {
 		ServiceName             string   `toml:"service-name"`
 	} `toml:"telemetry"`
 }
+
+// DefaultAppConfig returns app.toml values applied to validators for keys missing in config file
+func DefaultAppConfig() AppConfig {
+	var c AppConfig
+	c.Pruning = "default"
+	c.IavlCacheSize = 781250
+	c.API.Enable = true
+	c.API.MaxOpenConnections = 1000
+	c.API.RPCReadTimeout = 10
+	c.API.RPCMaxBodyBytes = 1000000
+	c.Grpc.Enable = true
+	return c
+}
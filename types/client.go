@@ -1,9 +1,19 @@
 package types
 
 type ClientConfig struct {
-	BroadcastMode  string `toml:"broadcast-mode"`
-	ChainID        string `toml:"chain-id"`
-	KeyringBackend string `toml:"keyring-backend"`
-	Node           string `toml:"node"`
-	Output         string `toml:"output"`
+	BroadcastMode         string `toml:"broadcast-mode" yaml:"broadcast-mode" json:"broadcast-mode,omitempty"`
+	ChainID               string `toml:"chain-id" yaml:"chain-id" json:"chain-id,omitempty"`
+	KeyringBackend        string `toml:"keyring-backend" yaml:"keyring-backend" json:"keyring-backend,omitempty"`
+	KeyringDefaultKeyname string `toml:"keyring-default-keyname" yaml:"keyring-default-keyname" json:"keyring-default-keyname,omitempty"`
+	Node                  string `toml:"node" yaml:"node" json:"node,omitempty"`
+	Output                string `toml:"output" yaml:"output" json:"output,omitempty"`
+}
+
+// DefaultClientConfig returns client.toml values applied for keys missing in config file, chain id, keyring
+// backend and node are set by the builder
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		BroadcastMode: "sync",
+		Output:        "text",
+	}
 }
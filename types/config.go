@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-	"time"
 )
 
 type IgniteConfig struct {
@@ -13,56 +12,37 @@ type IgniteConfig struct {
 		Name  string   `yaml:"name" json:"name,omitempty"`
 		Coins []string `yaml:"coins" json:"coins,omitempty"`
 	} `yaml:"accounts" json:"accounts"`
+	Build struct {
+		Binary  string   `yaml:"binary" json:"binary,omitempty"`   //node binary name
+		Main    string   `yaml:"main" json:"main,omitempty"`       //main package to build from source, default "."
+		Make    string   `yaml:"make" json:"make,omitempty"`       //make target (eg. install) instead of go build
+		LDFlags []string `yaml:"ldflags" json:"ldflags,omitempty"` //go build ldflags
+		Tags    []string `yaml:"tags" json:"tags,omitempty"`       //go build tags
+		Output  string   `yaml:"output" json:"output,omitempty"`   //binary cache directory
+	} `yaml:"build" json:"build"`
 	Client struct {
-		Openapi struct {
+		ClientConfig `yaml:",inline"` //default client.toml settings of validators
+		Openapi      struct {
 			Path string `yaml:"path" json:"path,omitempty"`
 		} `yaml:"openapi" json:"openapi"`
 	} `yaml:"client" json:"client"`
-	Validators []struct {
-		Name   string `yaml:"name" json:"name"`
-		Bonded string `yaml:"bonded" json:"bonded"`
-		Home   string `yaml:"home" json:"home"`
-		IP     string `yaml:"ip" json:"ip"`
-		App    struct {
-			MinimumGasPrices string `yaml:"minimum-gas-prices" json:"minimum-gas-prices"`
-			API              struct {
-				Enable            bool   `yaml:"enable" json:"enable,omitempty"`
-				EnabledUnsafeCors bool   `yaml:"enabled-unsafe-cors" json:"enabled-unsafe-cors,omitempty"`
-				Address           string `yaml:"address" json:"address,omitempty"`
-			} `yaml:"api"`
-			Grpc struct {
-				Enable  bool   `yaml:"enable" json:"enable,omitempty"`
-				Address string `yaml:"address" json:"address,omitempty"`
-			} `yaml:"grpc"`
-			GrpcWeb struct {
-				Address          string `yaml:"address" json:"address,omitempty"`
-				Enable           bool   `yaml:"enable" json:"enable,omitempty"`
-				EnableUnsafeCors bool   `yaml:"enable-unsafe-cors" json:"enable-unsafe-cors,omitempty"`
-			} `yaml:"grpc-web" json:"grpc-web"`
-		} `yaml:"app" json:"app"`
-		Config struct {
-			Consensus struct {
-				TimeoutCommit string `yaml:"timeout_commit" json:"timeout_commit,omitempty"`
-			} `yaml:"consensus" json:"consensus"`
-			ProxyApp string `yaml:"proxy_app" json:"proxy_app"`
-			Moniker  string `yaml:"moniker" json:"moniker"`
-			RPC      struct {
-				MaxBodyBytes string `yaml:"max_body_bytes" json:"max_body_bytes"`
-				Laddr        string `yaml:"laddr" json:"laddr,omitempty"`
-			} `yaml:"rpc" json:"rpc"`
-			P2P struct {
-				Laddr            string `yaml:"laddr" json:"laddr,omitempty"`
-				PersistentPeers  string `yaml:"persistent_peers" json:"persistent_peers,omitempty"`
-				AllowDuplicateIP bool   `yaml:"allow_duplicate_ip" json:"allow_duplicate_ip,omitempty"`
-			} `yaml:"p2p" json:"p2p"`
-			Instrumentation struct {
-				Prometheus           bool   `yaml:"prometheus" json:"prometheus"`
-				PrometheusListenAddr string `yaml:"prometheus_listen_addr" json:"prometheus_listen_addr"`
-			} `yaml:"instrumentation" json:"instrumentation"`
-		} `yaml:"config" json:"config"`
-	} `yaml:"validators" json:"validators"`
+	Validators []ValidatorConfig     `yaml:"validators" json:"validators"`
+	Hooks      map[string]StageHooks `yaml:"hooks" json:"hooks,omitempty"`     //lifecycle hooks by build stage name
+	Plugins    []string              `yaml:"plugins" json:"plugins,omitempty"` //chain plugins whose genesis mutators apply, eg. [hobby]
+	Faucet     struct {
+		Name            string   `yaml:"name" json:"name,omitempty"`                           //account name to send coins from
+		Coins           []string `yaml:"coins" json:"coins,omitempty"`                         //coins sent per request
+		CoinsMax        []string `yaml:"coins_max" json:"coins_max,omitempty"`                 //max balance of address per denom
+		RateLimitWindow string   `yaml:"rate_limit_window" json:"rate_limit_window,omitempty"` //min interval of requests per address, eg. 24h
+		Host            string   `yaml:"host" json:"host,omitempty"`                           //listen address, eg. 0.0.0.0:4500
+	} `yaml:"faucet" json:"faucet"`
+	Cosmovisor struct {
+		Enable   bool              `yaml:"enable" json:"enable"`
+		Genesis  string            `yaml:"genesis" json:"genesis,omitempty"`
+		Upgrades map[string]string `yaml:"upgrades" json:"upgrades,omitempty"`
+	} `yaml:"cosmovisor" json:"cosmovisor"`
 	Genesis struct {
-		ChainID         string `yaml:"chain_id" json:"chain_id""`
+		ChainID         string `yaml:"chain_id" json:"chain_id"`
 		InitialHeight   string `yaml:"initial_height" json:"initial_height"`
 		GenesisTime     string `yaml:"genesis_time" json:"genesis_time"`
 		ConsensusParams struct {
@@ -82,65 +62,8 @@ type IgniteConfig struct {
 				App string `yaml:"app" json:"app"`
 			} `yaml:"version" json:"version"`
 		} `yaml:"consensus_params" json:"consensus_params"`
+		//app_state of chain specific modules is free form, customized by genesis mutators of plugins
 		AppState struct {
-			Claims struct {
-				ClaimsRecords []interface{} `json:"claims_records" yaml:"claims_records"`
-				Params        struct {
-					AirdropStartTime   time.Time `json:"airdrop_start_time" yaml:"airdrop_start_time"`
-					AuthorizedChannels []string  `json:"authorized_channels" yaml:"authorized_channels"`
-					ClaimsDenom        string    `json:"claims_denom" yaml:"claims_denom"`
-					DurationOfDecay    string    `json:"duration_of_decay" yaml:"duration_of_decay"`
-					DurationUntilDecay string    `json:"duration_until_decay" yaml:"duration_until_decay"`
-					EnableClaims       bool      `json:"enable_claims" yaml:"enable_claims"`
-					EvmChannels        []string  `json:"evm_channels" yaml:"evm_channels"`
-				} `json:"params" yaml:"params"`
-			} `json:"claims" yaml:"claims"`
-			Evm struct {
-				Accounts []interface{} `json:"accounts"`
-				Params   struct {
-					ActivePrecompiles   []string `json:"active_precompiles" yaml:"active_precompiles"`
-					AllowUnprotectedTxs bool     `json:"allow_unprotected_txs" yaml:"allow_unprotected"`
-					ChainConfig         struct {
-						ArrowGlacierBlock   string `json:"arrow_glacier_block" yaml:"arrow_glacier_block"`
-						BerlinBlock         string `json:"berlin_block" yaml:"berlin_block"`
-						ByzantiumBlock      string `json:"byzantium_block" yaml:"byzantium_block"`
-						CancunBlock         string `json:"cancun_block" yaml:"cancun_block"`
-						ConstantinopleBlock string `json:"constantinople_block" yaml:"constantinople_block"`
-						DaoForkBlock        string `json:"dao_fork_block" yaml:"dao_fork_block"`
-						DaoForkSupport      bool   `json:"dao_fork_support" yaml:"dao_fork_support"`
-						Eip150Block         string `json:"eip150_block" yaml:"eip_150_block"`
-						Eip150Hash          string `json:"eip150_hash" yaml:"eip_150_hash"`
-						Eip155Block         string `json:"eip155_block" yaml:"eip_155_block"`
-						Eip158Block         string `json:"eip158_block" yaml:"eip_158_block"`
-						GrayGlacierBlock    string `json:"gray_glacier_block" yaml:"gray_glacier_block"`
-						HomesteadBlock      string `json:"homestead_block" yaml:"homestead_block"`
-						IstanbulBlock       string `json:"istanbul_block" yaml:"istanbul_block"`
-						LondonBlock         string `json:"london_block" yaml:"london_block"`
-						MergeNetsplitBlock  string `json:"merge_netsplit_block" yaml:"merge_netsplit_block"`
-						MuirGlacierBlock    string `json:"muir_glacier_block" yaml:"muir_glacier_block"`
-						PetersburgBlock     string `json:"petersburg_block" yaml:"petersburg_block"`
-						ShanghaiBlock       string `json:"shanghai_block" yaml:"shanghai_block"`
-					} `json:"chain_config"`
-					EnableCall   bool     `json:"enable_call" yaml:"enable_call"`
-					EnableCreate bool     `json:"enable_create" yaml:"enable_create"`
-					EvmDenom     string   `json:"evm_denom" yaml:"evm_denom"`
-					ExtraEips    []string `json:"extra_eips" yaml:"extra_eips"`
-				} `json:"params"`
-			} `json:"evm"`
-
-			Feemarket struct {
-				BlockGas string `json:"block_gas" yaml:"block_gas"`
-				Params   struct {
-					BaseFee                  string `json:"base_fee" yaml:"base_fee"`
-					BaseFeeChangeDenominator int    `json:"base_fee_change_denominator" yaml:"base_fee_change_denominator"`
-					ElasticityMultiplier     int    `json:"elasticity_multiplier" yaml:"elasticity_multiplier"`
-					EnableHeight             string `json:"enable_height" yaml:"enable_height"`
-					MinGasMultiplier         string `json:"min_gas_multiplier" yaml:"min_gas_multiplier"`
-					MinGasPrice              string `json:"min_gas_price" yaml:"min_gas_price"`
-					NoBaseFee                bool   `json:"no_base_fee" yaml:"no_base_fee"`
-				} `json:"params" yaml:"params"`
-			} `json:"feemarket" yaml:"feemarket"`
-
 			Bank struct {
 				DenomMetadata []struct {
 					Description string `yaml:"description" json:"description,omitempty"`
@@ -207,6 +130,65 @@ type IgniteConfig struct {
 	} `yaml:"genesis" json:"genesis"`
 }
 
+// StageHooks are hooks run before and after a build stage
+type StageHooks struct {
+	Before []HookConfig `yaml:"before" json:"before,omitempty"`
+	After  []HookConfig `yaml:"after" json:"after,omitempty"`
+}
+
+// HookConfig is a command line or script run by shell with COSMOS_CLI_* environment variables of the stage
+type HookConfig struct {
+	Run          string `yaml:"run" json:"run"`                               //command line or script path relative to config file
+	PerValidator bool   `yaml:"per_validator" json:"per_validator,omitempty"` //run once for every validator instead of once
+	OnFailure    string `yaml:"on_failure" json:"on_failure,omitempty"`       //abort (default), warn or ignore
+}
+
+type ValidatorConfig struct {
+	Name   string       `yaml:"name" json:"name"`
+	Bonded string       `yaml:"bonded" json:"bonded"`
+	Home   string       `yaml:"home" json:"home"`
+	IP     string       `yaml:"ip" json:"ip"`
+	Binary string       `yaml:"binary" json:"binary,omitempty"` //node binary of validator overrides build.binary
+	Client ClientConfig `yaml:"client" json:"client"`           //client.toml settings, node defaults to RPC of validator
+	App    struct {
+		MinimumGasPrices string `yaml:"minimum-gas-prices" json:"minimum-gas-prices"`
+		API              struct {
+			Enable            bool   `yaml:"enable" json:"enable,omitempty"`
+			EnabledUnsafeCors bool   `yaml:"enabled-unsafe-cors" json:"enabled-unsafe-cors,omitempty"`
+			Address           string `yaml:"address" json:"address,omitempty"`
+		} `yaml:"api"`
+		Grpc struct {
+			Enable  bool   `yaml:"enable" json:"enable,omitempty"`
+			Address string `yaml:"address" json:"address,omitempty"`
+		} `yaml:"grpc"`
+		GrpcWeb struct {
+			Address          string `yaml:"address" json:"address,omitempty"`
+			Enable           bool   `yaml:"enable" json:"enable,omitempty"`
+			EnableUnsafeCors bool   `yaml:"enable-unsafe-cors" json:"enable-unsafe-cors,omitempty"`
+		} `yaml:"grpc-web" json:"grpc-web"`
+	} `yaml:"app" json:"app"`
+	Config struct {
+		Consensus struct {
+			TimeoutCommit string `yaml:"timeout_commit" json:"timeout_commit,omitempty"`
+		} `yaml:"consensus" json:"consensus"`
+		ProxyApp string `yaml:"proxy_app" json:"proxy_app"`
+		Moniker  string `yaml:"moniker" json:"moniker"`
+		RPC      struct {
+			MaxBodyBytes string `yaml:"max_body_bytes" json:"max_body_bytes"`
+			Laddr        string `yaml:"laddr" json:"laddr,omitempty"`
+		} `yaml:"rpc" json:"rpc"`
+		P2P struct {
+			Laddr            string `yaml:"laddr" json:"laddr,omitempty"`
+			PersistentPeers  string `yaml:"persistent_peers" json:"persistent_peers,omitempty"`
+			AllowDuplicateIP bool   `yaml:"allow_duplicate_ip" json:"allow_duplicate_ip,omitempty"`
+		} `yaml:"p2p" json:"p2p"`
+		Instrumentation struct {
+			Prometheus           bool   `yaml:"prometheus" json:"prometheus"`
+			PrometheusListenAddr string `yaml:"prometheus_listen_addr" json:"prometheus_listen_addr"`
+		} `yaml:"instrumentation" json:"instrumentation"`
+	} `yaml:"config" json:"config"`
+}
+
 func (m IgniteConfig) GetAccountBalances(name string) string {
 	for _, a := range m.Accounts {
 		if a.Name == name {
@@ -225,6 +207,42 @@ func (m IgniteConfig) GetValidatorHost(strValidatorName string) string {
 	return "<N/A>"
 }
 
+func (m IgniteConfig) GetValidator(strValidatorName string) (int, *ValidatorConfig) {
+	for i := range m.Validators {
+		if m.Validators[i].Name == strValidatorName {
+			return i, &m.Validators[i]
+		}
+	}
+	return -1, nil
+}
+
+func (m IgniteConfig) GetAccountCoins(name string) []string {
+	for _, a := range m.Accounts {
+		if a.Name == name {
+			return a.Coins
+		}
+	}
+	return nil
+}
+
+// GetValidatorRPC returns RPC endpoint of validator made of its ip and rpc listen port. eg. tcp://172.20.0.101:26657
+func (m IgniteConfig) GetValidatorRPC(strValidatorName string) string {
+	for _, v := range m.Validators {
+		if v.Name == strValidatorName {
+			return fmt.Sprintf("tcp://%s:%s", v.IP, parseRPCPort(v.Config.RPC.Laddr))
+		}
+	}
+	return ""
+}
+
+func parseRPCPort(strAddr string) string {
+	u, err := url.Parse(strAddr)
+	if err != nil || u.Port() == "" {
+		return COSMOS_RPC_PORT
+	}
+	return u.Port()
+}
+
 func parseP2PPort(strAddr string) string {
 	u, err := url.Parse(strAddr)
 	if err != nil {
@@ -350,3 +368,19 @@ type CosmosConfig struct {
 		Namespace            string `toml:"namespace"`
 	} `toml:"instrumentation"`
 }
+
+// DefaultCosmosConfig returns config.toml values applied to validators for keys missing in config file, validators
+// of a local network share one IP so duplicate IPs are allowed
+func DefaultCosmosConfig() CosmosConfig {
+	var c CosmosConfig
+	c.LogLevel = "info"
+	c.LogFormat = "plain"
+	c.RPC.MaxBodyBytes = 1000000
+	c.P2P.AllowDuplicateIP = true
+	c.P2P.MaxNumInboundPeers = 40
+	c.P2P.MaxNumOutboundPeers = 10
+	c.Mempool.Size = 5000
+	c.Consensus.TimeoutPropose = "3s"
+	c.TxIndex.Indexer = "kv"
+	return c
+}
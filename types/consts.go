@@ -40,8 +40,151 @@ const (
 )
 
 const (
-	FILE_NAME_APP     = "app.toml"
-	FILE_NAME_CONFIG  = "config.toml"
-	FILE_NAME_GENESIS = "genesis.json"
-	CONFIG_SUBPATH    = "config"
+	DEFAULT_COMMISSION_RATE            = "0.10"
+	DEFAULT_COMMISSION_MAX_RATE        = "0.20"
+	DEFAULT_COMMISSION_MAX_CHANGE_RATE = "0.01"
+	DEFAULT_MIN_SELF_DELEGATION        = "1"
+	DEFAULT_GAS_ADJUSTMENT             = "1.5"
+	EXPECT_TX_TIMEOUT                  = 60
+)
+
+const (
+	FILE_NAME_APP                 = "app.toml"
+	FILE_NAME_CONFIG              = "config.toml"
+	FILE_NAME_GENESIS             = "genesis.json"
+	FILE_NAME_CLIENT              = "client.toml"
+	FILE_NAME_CREATE_VALIDATOR_TX = "create-validator.json"
+	FILE_NAME_FUND_TX             = "fund.json"
+	FILE_NAME_EXPORTED_GENESIS    = "exported-genesis.json"
+	FILE_NAME_NODE_KEY            = "node_key.json"
+	FILE_NAME_PRIV_VALIDATOR_KEY  = "priv_validator_key.json"
+	CONFIG_SUBPATH                = "config"
+)
+
+const (
+	QUERY_POLL_INTERVAL = 2  //seconds
+	QUERY_POLL_TIMEOUT  = 60 //seconds
+)
+
+const (
+	ENV_NAME_WORKSPACE           = "COSMOS_CLI_WORKSPACE"
+	DEFAULT_WORKSPACE_DIR        = "~/.cosmos-cli"
+	WORKSPACE_NETWORKS_SUBPATH   = "networks"
+	WORKSPACE_HOMES_SUBPATH      = "homes"
+	WORKSPACE_LOGS_SUBPATH       = "logs"
+	WORKSPACE_REPORTS_SUBPATH    = "reports"
+	WORKSPACE_BACKUPS_SUBPATH    = "backups"
+	WORKSPACE_SNAPSHOTS_SUBPATH  = "snapshots"
+	WORKSPACE_BIN_SUBPATH        = "bin"
+	FILE_NAME_JOURNAL            = "journal.jsonl"
+	FILE_NAME_LOG                = "cosmos-cli.log"
+	FILE_NAME_BUILD_REPORT       = "build-report.json"
+	JOURNAL_STATUS_OK            = "ok"
+	JOURNAL_STATUS_FAILED        = "failed"
+	JOURNAL_CMD_BUILD            = "build"
+	JOURNAL_CMD_VALIDATOR_ADD    = "validator add"
+	JOURNAL_CMD_NETWORK_CREATE   = "network create"
+	JOURNAL_STAGE_CREATE         = "create"
+	JOURNAL_CMD_SNAPSHOT_CREATE  = "snapshot create"
+	JOURNAL_CMD_SNAPSHOT_RESTORE = "snapshot restore"
+	JOURNAL_CMD_GENESIS_MIGRATE  = "genesis migrate"
+	JOURNAL_CMD_UPGRADE_SCHEDULE = "upgrade schedule"
+	JOURNAL_CMD_VERIFY           = "verify"
+	JOURNAL_CMD_TX               = "tx"
+)
+
+const (
+	STAGE_BUILD_BINARY         = "build_binary"
+	STAGE_PREFLIGHT            = "preflight"
+	STAGE_INIT_NODES           = "init_nodes"
+	STAGE_UPDATE_APP_CONFIG    = "update_app_config"
+	STAGE_UPDATE_COSMOS_CONFIG = "update_cosmos_config"
+	STAGE_UPDATE_CLIENT_CONFIG = "update_client_config"
+	STAGE_MERGE_GENESIS        = "merge_genesis"
+	STAGE_SYNC_GENESIS         = "sync_genesis"
+	STAGE_COSMOVISOR_LAYOUT    = "cosmovisor_layout"
+	STAGE_VERIFY               = "verify"
+	STAGE_SHOW_VALIDATORS      = "show_validators"
+)
+
+const (
+	HOOK_BEFORE            = "before"
+	HOOK_AFTER             = "after"
+	HOOK_ON_FAILURE_ABORT  = "abort"
+	HOOK_ON_FAILURE_WARN   = "warn"
+	HOOK_ON_FAILURE_IGNORE = "ignore"
+	HOOK_ENV_STAGE         = "COSMOS_CLI_STAGE"      //build stage name
+	HOOK_ENV_WHEN          = "COSMOS_CLI_HOOK"       //before or after
+	HOOK_ENV_CHAIN_ID      = "COSMOS_CLI_CHAIN_ID"   //chain id
+	HOOK_ENV_NODE_CMD      = "COSMOS_CLI_NODE_CMD"   //node binary
+	HOOK_ENV_CONFIG        = "COSMOS_CLI_CONFIG"     //config file path
+	HOOK_ENV_NODE0_HOME    = "COSMOS_CLI_NODE0_HOME" //home of the first validator
+	HOOK_ENV_HOMES         = "COSMOS_CLI_HOMES"      //homes of all validators separated by space
+	HOOK_ENV_VALIDATOR     = "COSMOS_CLI_VALIDATOR"  //validator name of per-validator hook
+	HOOK_ENV_HOME          = "COSMOS_CLI_HOME"       //validator home of per-validator hook (node0 home otherwise)
+	HOOK_ENV_GENESIS       = "COSMOS_CLI_GENESIS"    //genesis file of COSMOS_CLI_HOME
+)
+
+const (
+	SOURCE_DEFAULT_MAIN = "."
+	SOURCE_DIRTY_SUFFIX = "-dirty"
+)
+
+const (
+	TIME_FORMAT_FILE_NAME = "20060102-150405"
+	TIME_FORMAT_DISPLAY   = "2006-01-02 15:04:05"
+)
+
+const (
+	SNAPSHOT_FILE_EXT       = ".tar.gz"
+	SNAPSHOT_CHECKSUM_EXT   = ".sha256"
+	SNAPSHOT_MANIFEST       = "manifest.json"
+	SNAPSHOT_CHECKSUMS      = "checksums.json"
+	SNAPSHOT_HOMES_SUBPATH  = "homes"
+	SNAPSHOT_STAGING_PREFIX = ".restore-"
+	DEFAULT_SNAPSHOT_DIR    = "snapshots"
+)
+
+const (
+	COSMOVISOR_SUBPATH          = "cosmovisor"
+	COSMOVISOR_GENESIS_SUBPATH  = "genesis"
+	COSMOVISOR_UPGRADES_SUBPATH = "upgrades"
+	COSMOVISOR_BIN_SUBPATH      = "bin"
+)
+
+const (
+	VOTE_OPTION_YES        = "yes"
+	DEFAULT_UPGRADE_TITLE  = "software upgrade %s"
+	UPGRADE_WATCH_INTERVAL = 3  //seconds
+	UPGRADE_HALT_STALL     = 30 //seconds without new block at upgrade height regarded as halted
+)
+
+const (
+	TX_ACTION_SEND            = "send"
+	TX_ACTION_DELEGATE        = "delegate"
+	TX_ACTION_VOTE            = "vote"
+	TX_ACTION_SUBMIT_PROPOSAL = "submit-proposal"
+)
+
+const (
+	FAUCET_DEFAULT_LISTEN      = "0.0.0.0:4500"
+	FAUCET_DEFAULT_RATE_WINDOW = "24h"
+)
+
+const (
+	MONITOR_DEFAULT_INTERVAL = 5  //seconds
+	MONITOR_DEFAULT_STALL    = 30 //seconds without new block regarded as stalled
+	MONITOR_MISSED_LOOKBACK  = 50 //max heights of commits checked for missed blocks per round
+)
+
+const (
+	NODE_STATUS_OK          = "ok"
+	NODE_STATUS_UNREACHABLE = "unreachable"
+	NODE_STATUS_STALLED     = "stalled"
+	NODE_STATUS_FORKED      = "forked"
+)
+
+const (
+	WAIT_DEFAULT_INTERVAL = 1   //seconds
+	WAIT_DEFAULT_TIMEOUT  = 300 //seconds
 )
@@ -1,13 +1,27 @@
 package types
 
+import "time"
+
 type Option struct {
-	Debug          bool   // debug mode on
-	ConfigPath     string // config file path
-	NodeCmd        string // chain node command
-	DefaultDenom   string // default denom
-	ChainID        string // chain id
-	KeyPhrase      string // pass phrase to protect keys
-	KeyringBackend string // keyring backend
+	Debug          bool     // debug mode on
+	ConfigPath     string   // config file path
+	ConfigOverlays []string // overlay config files deep merged into config file in order
+	NodeCmd        string   // chain node command
+	DefaultDenom   string   // default denom
+	ChainID        string   // chain id
+	KeyPhrase      string   // pass phrase to protect keys
+	KeyringBackend string   // keyring backend
+	Network        string   // named network in workspace
+	HomeRoot       string   // root directory of relative validator homes
+	ReportPath     string   // build report file path
+	FromSource     string   // chain app repository to build node binary from
+	LDFlags        []string // go build ldflags overrides build.ldflags
+	BuildTags      []string // go build tags overrides build.tags
+	BuildOutput    string   // binary cache directory overrides build.output
+	AllowedRoots   []string // validator homes are only allowed to be deleted inside these roots
+	Force          bool     // delete existing validator homes without confirmation
+	Backup         bool     // archive existing validator homes before deleting and keep config files as .bak on update
+	BackupDir      string   // directory of home archives
 }
 
 type NodePeer struct {
@@ -15,3 +29,74 @@ type NodePeer struct {
 	Peer            string
 	PersistentPeers []string
 }
+
+type ValidatorOption struct {
+	Option        // common option
+	Name   string // name of validator to join the network
+	From   string // account name to fund the new validator
+	Node   string // RPC endpoint to broadcast transactions, generate only if empty
+	Fees   string // fees to pay along with transactions
+}
+
+type SnapshotOption struct {
+	Option                        // common option
+	Name        string            // snapshot name
+	Dir         string            // snapshots directory
+	RestoreRoot string            // restore validator homes into this root directory by validator name
+	HomeMap     map[string]string // restore validator homes to remapped paths by validator name
+}
+
+type GenesisOption struct {
+	Option               // common option
+	NewNodeCmd    string // chain node command of new binary (default node command)
+	NewChainID    string // chain id of new chain (default chain id of config file)
+	InitialHeight string // initial height of new chain (default exported height + 1)
+	GenesisTime   string // genesis time of new chain in RFC3339 (default now)
+	ExportHeight  int64  // height to export state at (default latest height)
+	NoReset       bool   // keep node data instead of resetting it for the new chain
+}
+
+type UpgradeOption struct {
+	Option                // common option
+	Name    string        // upgrade plan name
+	Height  int64         // upgrade height
+	Info    string        // upgrade plan info
+	Deposit string        // proposal deposit (default gov min deposit of config file)
+	From    string        // proposer account name (default first validator)
+	Node    string        // RPC endpoint (default RPC endpoint of first validator)
+	Fees    string        // fees to pay along with transactions
+	NoWatch bool          // do not watch heights until the upgrade height
+	Timeout time.Duration // timeout of watching heights
+}
+
+type TxOption struct {
+	Option             // common option
+	Action    string   // transaction action: send, delegate, vote or submit-proposal
+	Args      []string // positional arguments of action
+	From      string   // account name to sign transactions (every validator votes if empty)
+	Node      string   // RPC endpoint (default RPC endpoint of signer validator or first validator)
+	Fees      string   // fees to pay along with transactions
+	GasPrices string   // gas prices (default minimum-gas-prices of signer validator)
+}
+
+type FaucetOption struct {
+	Option        // common option
+	Listen string // listen address (default faucet.host of config file)
+	Node   string // RPC endpoint (default RPC endpoint of first validator)
+}
+
+type MonitorOption struct {
+	Option                 // common option
+	Interval time.Duration // interval of polling validator RPC endpoints
+	Stall    time.Duration // duration without new block regarded as stalled
+	Count    int           // rounds of polling (forever if zero)
+	ExitCode bool          // exit non-zero once an issue is found
+}
+
+type WaitOption struct {
+	Option                 // common option
+	Height   int64         // target height
+	Quorum   int           // count of validators to reach target height (all if zero)
+	Interval time.Duration // interval of polling validator RPC endpoints
+	Timeout  time.Duration // timeout of waiting
+}
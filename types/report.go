@@ -0,0 +1,26 @@
+package types
+
+import "time"
+
+// BuildReport describes a built network
+type BuildReport struct {
+	ChainID      string             `json:"chain_id"`
+	NodeCmd      string             `json:"node_cmd"`
+	ConfigPath   string             `json:"config_path"`
+	GenesisHash  string             `json:"genesis_hash"`
+	SourceRepo   string             `json:"source_repo,omitempty"`
+	SourceCommit string             `json:"source_commit,omitempty"`
+	Time         time.Time          `json:"time"`
+	Validators   []*ValidatorReport `json:"validators"`
+}
+
+type ValidatorReport struct {
+	Name    string `json:"name"`
+	Home    string `json:"home"`
+	Moniker string `json:"moniker"`
+	AccAddr string `json:"acc_addr"`
+	ValAddr string `json:"val_addr"`
+	Peer    string `json:"peer"`
+	Binary  string `json:"binary"`
+	Version string `json:"version"`
+}
@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// SnapshotManifest describes content of a network snapshot bundle
+type SnapshotManifest struct {
+	Name       string               `json:"name"`
+	ChainID    string               `json:"chain_id"`
+	Time       time.Time            `json:"time"`
+	Validators []*SnapshotValidator `json:"validators"`
+	Files      map[string]string    `json:"files"` //bundle entry name => SHA-256
+}
+
+type SnapshotValidator struct {
+	Name string `json:"name"`
+	Home string `json:"home"`
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TarGz archives directory strSrcDir into gzip compressed tarball strDstFile, entries are stored
+// with path relative to strSrcDir and prefixed by strPrefix (may be empty)
+func TarGz(strSrcDir, strDstFile, strPrefix string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(strDstFile), 0o755); err != nil {
+		return err
+	}
+	var file *os.File
+	if file, err = os.Create(strDstFile); err != nil {
+		return err
+	}
+	defer file.Close()
+	zw := gzip.NewWriter(file)
+	tw := tar.NewWriter(zw)
+	if err = AddDirToTar(tw, strSrcDir, strPrefix); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// AddDirToTar writes directory strSrcDir into tar writer with entry names prefixed by strPrefix
+func AddDirToTar(tw *tar.Writer, strSrcDir, strPrefix string) error {
+	return AddDirToTarWithSums(tw, strSrcDir, strPrefix, nil)
+}
+
+// AddDirToTarWithSums writes directory strSrcDir into tar writer like AddDirToTar and puts SHA-256 of the content
+// written for every regular file into sums by entry name, so checksums always match the archived bytes even if
+// files are being written meanwhile
+func AddDirToTarWithSums(tw *tar.Writer, strSrcDir, strPrefix string, sums map[string]string) error {
+	return filepath.Walk(strSrcDir, func(strPath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		strRel, err := filepath.Rel(strSrcDir, strPath)
+		if err != nil {
+			return err
+		}
+		strName := filepath.ToSlash(filepath.Join(strPrefix, strRel))
+		if strName == "." {
+			return nil
+		}
+		var strLink string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if strLink, err = os.Readlink(strPath); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, strLink)
+		if err != nil {
+			return err
+		}
+		hdr.Name = strName
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(strPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		//copy the size of header exactly, a file growing meanwhile is archived as it was at stat
+		if _, err = io.CopyN(io.MultiWriter(tw, h), f, hdr.Size); err != nil {
+			return fmt.Errorf("archive %s error [%s]", strPath, err)
+		}
+		if sums != nil {
+			sums[strName] = hex.EncodeToString(h.Sum(nil))
+		}
+		return nil
+	})
+}
+
+// UntarGz extracts gzip compressed tarball strSrcFile into directory strDstDir. the mapper (may be nil)
+// returns the destination path of an entry name or empty string to skip it
+func UntarGz(strSrcFile, strDstDir string, mapper func(strName string) string) (err error) {
+	var file *os.File
+	if file, err = os.Open(strSrcFile); err != nil {
+		return err
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		strPath := filepath.Join(strDstDir, filepath.FromSlash(hdr.Name))
+		if mapper != nil {
+			if strPath = mapper(hdr.Name); strPath == "" {
+				continue
+			}
+		}
+		strPath = filepath.Clean(strPath)
+		if mapper == nil && !strings.HasPrefix(strPath, filepath.Clean(strDstDir)+string(filepath.Separator)) {
+			return fmt.Errorf("illegal entry %s in archive %s", hdr.Name, strSrcFile)
+		}
+		if err = extractEntry(tr, hdr, strPath); err != nil {
+			return err
+		}
+	}
+}
+
+func extractEntry(tr *tar.Reader, hdr *tar.Header, strPath string) (err error) {
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(strPath, os.FileMode(hdr.Mode)|0o700)
+	case tar.TypeSymlink:
+		if err = os.MkdirAll(filepath.Dir(strPath), 0o755); err != nil {
+			return err
+		}
+		_ = os.Remove(strPath)
+		return os.Symlink(hdr.Linkname, strPath)
+	case tar.TypeReg:
+		if err = os.MkdirAll(filepath.Dir(strPath), 0o755); err != nil {
+			return err
+		}
+		var f *os.File
+		if f, err = os.OpenFile(strPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(hdr.Mode)); err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(f, tr)
+		return err
+	}
+	return nil
+}
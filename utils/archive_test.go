@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDirToTarWithSums(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config", "genesis.json"), []byte(`{"chain_id":"test_9000-1"}`), 0o644))
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	sums := make(map[string]string)
+	require.NoError(t, AddDirToTarWithSums(tw, root, "homes/node1", sums))
+	require.NoError(t, tw.Close())
+	require.Equal(t, map[string]string{"homes/node1/config/genesis.json": BytesSHA256([]byte(`{"chain_id":"test_9000-1"}`))}, sums)
+
+	tr := tar.NewReader(&buf)
+	var names []string
+	for hdr, err := tr.Next(); err == nil; hdr, err = tr.Next() {
+		names = append(names, hdr.Name)
+	}
+	require.Equal(t, []string{"homes/node1/", "homes/node1/config/", "homes/node1/config/genesis.json"}, names)
+}
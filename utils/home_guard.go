@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// protectedPaths are directories never allowed to be removed as a validator home
+var protectedPaths = []string{
+	"/", "/home", "/media", "/mnt", "/opt", "/root", "/srv", "/tmp",
+}
+
+// protectedTrees are system directories nothing inside of which is allowed to be removed as a validator home
+var protectedTrees = []string{
+	"/bin", "/boot", "/dev", "/etc", "/lib", "/lib32", "/lib64", "/proc", "/run", "/sbin", "/sys", "/usr", "/var",
+}
+
+// CheckRemovableHome returns an error if strHome is not safe to be removed as a validator home.
+// the home must be an absolute path at least two levels deep, not a system directory, nothing inside
+// a system directory tree, not a user home and strictly inside one of allowed roots.
+func CheckRemovableHome(strHome string, allowedRoots []string) error {
+	if strings.TrimSpace(strHome) == "" {
+		return fmt.Errorf("home path is empty")
+	}
+	strPath, err := resolvePath(strHome)
+	if err != nil {
+		return fmt.Errorf("resolve home %s error [%s]", strHome, err)
+	}
+	for _, p := range protectedPaths {
+		if strPath == p {
+			return fmt.Errorf("home %s is a protected system directory", strHome)
+		}
+	}
+	for _, p := range protectedTrees {
+		if strPath == p || strings.HasPrefix(strPath, p+string(filepath.Separator)) {
+			return fmt.Errorf("home %s is inside protected system directory %s", strHome, p)
+		}
+	}
+	if filepath.Dir(strPath) == "/home" {
+		return fmt.Errorf("home %s is a user home directory", strHome)
+	}
+	if strUserHome, err := os.UserHomeDir(); err == nil {
+		if strUserHome, err = resolvePath(strUserHome); err == nil && strPath == strUserHome {
+			return fmt.Errorf("home %s is the user home directory", strHome)
+		}
+	}
+	if len(strings.Split(strings.Trim(strPath, string(filepath.Separator)), string(filepath.Separator))) < 2 {
+		return fmt.Errorf("home %s is too close to file system root", strHome)
+	}
+	if len(allowedRoots) == 0 {
+		return fmt.Errorf("no allowed root specified to remove home %s", strHome)
+	}
+	for _, strRoot := range allowedRoots {
+		if strRoot, err = resolvePath(strRoot); err != nil {
+			continue
+		}
+		if strings.HasPrefix(strPath, strRoot+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("home %s is outside of allowed roots %v", strHome, allowedRoots)
+}
+
+// HasChainData reports whether home contains data, config or keyring of an initialized chain node
+func HasChainData(strHome string) bool {
+	for _, strName := range []string{"data", "config", "keyring-file", "keyring-test"} {
+		if _, err := os.Stat(filepath.Join(strHome, strName)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmptyDir reports whether directory is empty or does not exist
+func IsEmptyDir(strDir string) bool {
+	entries, err := os.ReadDir(strDir)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	return len(entries) == 0
+}
+
+// resolvePath makes path absolute and resolves symbolic links of its existing part
+func resolvePath(strPath string) (string, error) {
+	strPath, err := filepath.Abs(strPath)
+	if err != nil {
+		return "", err
+	}
+	var strRest string
+	for strDir := strPath; ; strDir = filepath.Dir(strDir) {
+		if strReal, err := filepath.EvalSymlinks(strDir); err == nil {
+			return filepath.Join(strReal, strRest), nil
+		}
+		if strDir == filepath.Dir(strDir) {
+			return strPath, nil
+		}
+		strRest = filepath.Join(filepath.Base(strDir), strRest)
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRemovableHome(t *testing.T) {
+	root := t.TempDir()
+	strUserHome, _ := os.UserHomeDir()
+	cases := []struct {
+		name    string
+		home    string
+		roots   []string
+		wantErr bool
+	}{
+		{"empty", "", nil, true},
+		{"root", "/", nil, true},
+		{"system dir", "/usr", nil, true},
+		{"user home", strUserHome, nil, true},
+		{"too shallow", "/data", nil, true},
+		{"dotted escape", filepath.Join(root, "node1", "..", ".."), []string{root}, true},
+		{"no roots", "/data/node1", nil, true},
+		{"system tree", "/usr/local/node1", []string{"/usr"}, true},
+		{"var tree", "/var/lib", nil, true},
+		{"etc tree", "/etc/ssl", []string{"/"}, true},
+		{"other user home", "/home/otheruser", []string{"/home"}, true},
+		{"inside user home", "/home/otheruser/.hobby", []string{"/home"}, false},
+		{"inside root", filepath.Join(root, "node1"), []string{root}, false},
+		{"root itself", root, []string{root}, true},
+		{"outside root", "/data/node1", []string{root}, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRemovableHome(tt.home, tt.roots)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsEmptyDir(t *testing.T) {
+	root := t.TempDir()
+	require.True(t, IsEmptyDir(root))
+	require.True(t, IsEmptyDir(filepath.Join(root, "missing")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("keep"), 0o600))
+	require.False(t, IsEmptyDir(root))
+	require.False(t, HasChainData(root))
+}
+
+func TestTarGz(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "config", "genesis.json"), []byte(`{}`), 0o600))
+	require.True(t, HasChainData(src))
+
+	strTarball := filepath.Join(t.TempDir(), "home.tar.gz")
+	require.NoError(t, TarGz(src, strTarball, "node1"))
+
+	dst := t.TempDir()
+	require.NoError(t, UntarGz(strTarball, dst, nil))
+	data, err := os.ReadFile(filepath.Join(dst, "node1", "config", "genesis.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{}`, string(data))
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadNodeID returns node ID of node_key.json which is hex encoded address (first 20 bytes of SHA-256)
+// of the ed25519 public key, the same as '<node> tendermint show-node-id'
+func LoadNodeID(strNodeKeyPath string) (string, error) {
+	data, err := os.ReadFile(strNodeKeyPath)
+	if err != nil {
+		return "", err
+	}
+	var nodeKey struct {
+		PrivKey struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"priv_key"`
+	}
+	if err = json.Unmarshal(data, &nodeKey); err != nil {
+		return "", fmt.Errorf("decode node key %s error: %s", strNodeKeyPath, err)
+	}
+	var priv []byte
+	if priv, err = base64.StdEncoding.DecodeString(nodeKey.PrivKey.Value); err != nil {
+		return "", fmt.Errorf("decode node key %s error: %s", strNodeKeyPath, err)
+	}
+	if len(priv) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("node key %s type %s is not ed25519", strNodeKeyPath, nodeKey.PrivKey.Type)
+	}
+	pub := ed25519.PrivateKey(priv).Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:20]), nil
+}
+
+// LoadValidatorAddress returns consensus address of priv_validator_key.json, the upper case hex address listed by
+// /validators and signatures of commits
+func LoadValidatorAddress(strPrivValidatorKeyPath string) (string, error) {
+	data, err := os.ReadFile(strPrivValidatorKeyPath)
+	if err != nil {
+		return "", err
+	}
+	var privValidatorKey struct {
+		Address string `json:"address"`
+	}
+	if err = json.Unmarshal(data, &privValidatorKey); err != nil {
+		return "", fmt.Errorf("decode validator key %s error: %s", strPrivValidatorKeyPath, err)
+	}
+	if privValidatorKey.Address == "" {
+		return "", fmt.Errorf("validator key %s has no address", strPrivValidatorKeyPath)
+	}
+	return privValidatorKey.Address, nil
+}
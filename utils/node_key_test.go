@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNodeID(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	strPath := filepath.Join(t.TempDir(), "node_key.json")
+	strKey := fmt.Sprintf(`{"priv_key":{"type":"tendermint/PrivKeyEd25519","value":"%s"}}`, base64.StdEncoding.EncodeToString(priv))
+	require.NoError(t, os.WriteFile(strPath, []byte(strKey), 0o600))
+
+	strID, err := LoadNodeID(strPath)
+	require.NoError(t, err)
+	sum := sha256.Sum256(pub)
+	require.Equal(t, hex.EncodeToString(sum[:20]), strID)
+	require.Len(t, strID, 40)
+
+	require.NoError(t, os.WriteFile(strPath, []byte(`{"priv_key":{"type":"x","value":"AAAA"}}`), 0o600))
+	_, err = LoadNodeID(strPath)
+	require.Error(t, err)
+}
+
+func TestLoadValidatorAddress(t *testing.T) {
+	strPath := filepath.Join(t.TempDir(), "priv_validator_key.json")
+	require.NoError(t, os.WriteFile(strPath, []byte(`{"address":"0A1B2C","pub_key":{"type":"tendermint/PubKeyEd25519","value":"AAAA"}}`), 0o600))
+	strAddr, err := LoadValidatorAddress(strPath)
+	require.NoError(t, err)
+	require.Equal(t, "0A1B2C", strAddr)
+
+	require.NoError(t, os.WriteFile(strPath, []byte(`{}`), 0o600))
+	_, err = LoadValidatorAddress(strPath)
+	require.Error(t, err)
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"github.com/civet148/cosmos-cli/types"
 	"github.com/civet148/log"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
 type CmdExecutor struct {
 	Debug bool
+	Env   []string // extra environment variables (KEY=VALUE) appended to environment of current process
+	Dir   string   // working directory of commands, current directory if empty
 }
 
 func NewCmdExecutor(debug bool) *CmdExecutor {
@@ -20,6 +25,10 @@ func NewCmdExecutor(debug bool) *CmdExecutor {
 
 func (m *CmdExecutor) Run(name string, args ...string) (output string, err error) {
 	cmd := exec.Command(name, args...)
+	cmd.Dir = m.Dir
+	if len(m.Env) != 0 {
+		cmd.Env = append(os.Environ(), m.Env...)
+	}
 	log.Infof("execute [%s %v]...", name, FmtStringArgs(args...))
 	var data []byte
 	data, err = cmd.CombinedOutput()
@@ -65,3 +74,36 @@ func FmtStringArgs(args ...string) string {
 	}
 	return fmt.Sprint(as...)
 }
+
+// Confirm asks user a yes/no question on terminal, it returns false if stdin is not a terminal
+func Confirm(strQuestion string) bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	var strAnswer string
+	fmt.Printf("%s [y/N] ", strQuestion)
+	_, _ = fmt.Scanln(&strAnswer)
+	strAnswer = strings.ToLower(strings.TrimSpace(strAnswer))
+	return strAnswer == "y" || strAnswer == "yes"
+}
+
+// CopyFile copies file content from strSrc to strDst with file mode
+func CopyFile(strSrc, strDst string, mode os.FileMode) (err error) {
+	var src, dst *os.File
+	if src, err = os.Open(strSrc); err != nil {
+		return err
+	}
+	defer src.Close()
+	if err = os.MkdirAll(filepath.Dir(strDst), 0o755); err != nil {
+		return err
+	}
+	if dst, err = os.OpenFile(strDst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode); err != nil {
+		return err
+	}
+	defer dst.Close()
+	if _, err = io.Copy(dst, src); err != nil {
+		return err
+	}
+	return dst.Sync()
+}
@@ -1,10 +1,12 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/civet148/cosmos-cli/types"
 	"github.com/civet148/log"
 	"net/url"
 	"path/filepath"
+	"strings"
 )
 
 // ParseP2PPort parse comsos p2p port from listen address. eg. "tcp://0.0.0.0:26656"
@@ -20,3 +22,27 @@ func ParseP2PPort(strAddr string) string {
 func MakeCosmosConfigPath(strHome, strFileName string) string {
 	return filepath.Join(strHome, types.CONFIG_SUBPATH, strFileName)
 }
+
+// LastLine returns the last line of command output (eg. the address printed after an expect passphrase prompt)
+func LastLine(strOutput string) string {
+	strOutput = strings.TrimSpace(strOutput)
+	idx := strings.LastIndex(strOutput, "\n")
+	if idx >= 0 {
+		strOutput = strOutput[idx+1:]
+	}
+	return strings.TrimSpace(strOutput)
+}
+
+// FormatBytes formats size in bytes to human readable string. eg. 1.5MiB
+func FormatBytes(nSize int64) string {
+	const unit = 1024
+	if nSize < unit {
+		return fmt.Sprintf("%dB", nSize)
+	}
+	div, exp := int64(unit), 0
+	for n := nSize / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(nSize)/float64(div), "KMGTPE"[exp])
+}
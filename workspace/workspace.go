@@ -0,0 +1,225 @@
+// Package workspace manages named networks side by side, each network keeps its own
+// config file, state journal, validator homes, logs and reports in a separate directory.
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/civet148/cosmos-cli/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Workspace is the root directory of all named networks
+type Workspace struct {
+	strRoot string
+}
+
+// New returns workspace on root directory. if root is empty, the directory is taken from
+// environment variable COSMOS_CLI_WORKSPACE and falls back to ~/.cosmos-cli
+func New(strRoot string) *Workspace {
+	if strRoot == "" {
+		strRoot = os.Getenv(types.ENV_NAME_WORKSPACE)
+	}
+	if strRoot == "" {
+		strRoot = types.DEFAULT_WORKSPACE_DIR
+	}
+	return &Workspace{
+		strRoot: ExpandHome(strRoot),
+	}
+}
+
+// Root returns workspace root directory
+func (w *Workspace) Root() string {
+	return w.strRoot
+}
+
+// Network returns network of name in workspace, the network may not exist yet
+func (w *Workspace) Network(strName string) *Network {
+	return &Network{
+		Name: strName,
+		Dir:  filepath.Join(w.strRoot, types.WORKSPACE_NETWORKS_SUBPATH, strName),
+	}
+}
+
+// Networks returns all networks in workspace ordered by name
+func (w *Workspace) Networks() (networks []*Network, err error) {
+	var entries []os.DirEntry
+	entries, err = os.ReadDir(filepath.Join(w.strRoot, types.WORKSPACE_NETWORKS_SUBPATH))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		n := w.Network(e.Name())
+		if n.Exists() {
+			networks = append(networks, n)
+		}
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Name < networks[j].Name
+	})
+	return networks, nil
+}
+
+// Network is a named network in workspace
+type Network struct {
+	Name string // network name
+	Dir  string // network directory
+}
+
+// JournalEntry is a state record of network appended by commands run against it
+type JournalEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Stage   string    `json:"stage"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (n *Network) ConfigPath() string {
+	return filepath.Join(n.Dir, types.DEFAULT_CONFIG_FILE)
+}
+
+func (n *Network) HomesDir() string {
+	return filepath.Join(n.Dir, types.WORKSPACE_HOMES_SUBPATH)
+}
+
+func (n *Network) LogsDir() string {
+	return filepath.Join(n.Dir, types.WORKSPACE_LOGS_SUBPATH)
+}
+
+func (n *Network) ReportsDir() string {
+	return filepath.Join(n.Dir, types.WORKSPACE_REPORTS_SUBPATH)
+}
+
+func (n *Network) BackupsDir() string {
+	return filepath.Join(n.Dir, types.WORKSPACE_BACKUPS_SUBPATH)
+}
+
+func (n *Network) SnapshotsDir() string {
+	return filepath.Join(n.Dir, types.WORKSPACE_SNAPSHOTS_SUBPATH)
+}
+
+func (n *Network) JournalPath() string {
+	return filepath.Join(n.Dir, types.FILE_NAME_JOURNAL)
+}
+
+func (n *Network) LogPath() string {
+	return filepath.Join(n.LogsDir(), types.FILE_NAME_LOG)
+}
+
+// ReportPath returns path of the latest build report
+func (n *Network) ReportPath() string {
+	return filepath.Join(n.ReportsDir(), types.FILE_NAME_BUILD_REPORT)
+}
+
+// Exists reports whether network has been created
+func (n *Network) Exists() bool {
+	_, err := os.Stat(n.ConfigPath())
+	return err == nil
+}
+
+// Create makes network directories and copies config file into network
+func (n *Network) Create(strConfigPath string) (err error) {
+	if err = ValidateName(n.Name); err != nil {
+		return err
+	}
+	if n.Exists() {
+		return fmt.Errorf("network %s already exists", n.Name)
+	}
+	var data []byte
+	if data, err = os.ReadFile(strConfigPath); err != nil {
+		return err
+	}
+	for _, strDir := range []string{n.Dir, n.HomesDir(), n.LogsDir(), n.ReportsDir()} {
+		if err = os.MkdirAll(strDir, 0o755); err != nil {
+			return err
+		}
+	}
+	if err = os.WriteFile(n.ConfigPath(), data, 0o644); err != nil {
+		return err
+	}
+	return n.Journal(&JournalEntry{Command: types.JOURNAL_CMD_NETWORK_CREATE, Stage: types.JOURNAL_STAGE_CREATE, Status: types.JOURNAL_STATUS_OK})
+}
+
+// Remove deletes network directory including validator homes
+func (n *Network) Remove() error {
+	if err := ValidateName(n.Name); err != nil {
+		return err
+	}
+	if !n.Exists() {
+		return fmt.Errorf("network %s not found", n.Name)
+	}
+	return os.RemoveAll(n.Dir)
+}
+
+// Journal appends an entry to network state journal
+func (n *Network) Journal(entry *JournalEntry) (err error) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	var data []byte
+	if data, err = json.Marshal(entry); err != nil {
+		return err
+	}
+	var file *os.File
+	file, err = os.OpenFile(n.JournalPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// ReadJournal reads all entries of network state journal
+func (n *Network) ReadJournal() (entries []*JournalEntry, err error) {
+	var file *os.File
+	if file, err = os.Open(n.JournalPath()); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("journal %s corrupted: %s", n.JournalPath(), err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ValidateName checks network name is usable as a directory name
+func ValidateName(strName string) error {
+	if strName == "" || strName == "." || strName == ".." || strings.ContainsAny(strName, `/\ `) {
+		return fmt.Errorf("invalid network name %q", strName)
+	}
+	return nil
+}
+
+// ExpandHome replaces leading ~ of path with user home directory
+func ExpandHome(strPath string) string {
+	if strings.HasPrefix(strPath, "~") {
+		strPath = strings.Replace(strPath, "~", "$HOME", 1)
+		strPath = os.ExpandEnv(strPath)
+	}
+	return strPath
+}
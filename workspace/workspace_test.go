@@ -0,0 +1,43 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/civet148/cosmos-cli/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkLifecycle(t *testing.T) {
+	root := t.TempDir()
+	strConfig := filepath.Join(root, "config.yml")
+	require.NoError(t, os.WriteFile(strConfig, []byte("version: 1\n"), 0o644))
+
+	ws := New(root)
+	for _, name := range []string{"staging", "devnet"} {
+		require.NoError(t, ws.Network(name).Create(strConfig))
+	}
+	require.Error(t, ws.Network("devnet").Create(strConfig))
+	require.Error(t, ws.Network("../escape").Create(strConfig))
+
+	networks, err := ws.Networks()
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	require.Equal(t, "devnet", networks[0].Name)
+	require.DirExists(t, networks[0].HomesDir())
+
+	n := networks[0]
+	require.NoError(t, n.Journal(&JournalEntry{Command: types.JOURNAL_CMD_BUILD, Stage: types.STAGE_INIT_NODES, Status: types.JOURNAL_STATUS_FAILED, Error: "boom"}))
+	entries, err := n.ReadJournal()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, types.STAGE_INIT_NODES, entries[1].Stage)
+	require.Equal(t, "boom", entries[1].Error)
+
+	require.NoError(t, n.Remove())
+	require.False(t, n.Exists())
+	networks, err = ws.Networks()
+	require.NoError(t, err)
+	require.Len(t, networks, 1)
+}